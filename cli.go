@@ -0,0 +1,222 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"tunnels/internal/config"
+)
+
+// CLI 종료 코드
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2 // 잘못된 사용법
+
+	exitNotRunning = 3 // 실행 중인 인스턴스가 없거나 제어 API가 비활성화됨
+)
+
+// usage 하위 명령 사용법
+const usage = `사용법: tunnels [설정파일]           트레이 앱 실행
+       tunnels --headless [설정파일] 트레이 없이 실행 (서버, 컨테이너용)
+       tunnels <명령> [옵션]
+
+명령:
+  status [이름]      터널 상태 출력
+  up <이름>          터널 시작 (--wait 초: 연결될 때까지 대기, 기본값 30)
+  down <이름>        터널 중지
+  restart <이름>     터널 재시작 (--wait 초)
+  reload             설정 파일 다시 로드
+  validate           설정 파일 검사 (실행 중인 인스턴스 없이 동작)
+  config backups     설정 파일 백업 목록 (1이 가장 최근)
+  config restore [번호|파일]
+                     백업으로 설정 파일 복원 (기본값 가장 최근 백업)
+  config import [Host...]
+                     ~/.ssh/config의 LocalForward/RemoteForward/DynamicForward를 터널로 가져오기
+                     (--ssh-config 경로, --alias: 접속 정보 대신 ssh_alias로 참조,
+                      --enable: 활성화 상태로 추가, --dry-run: 저장하지 않음)
+  encrypt            표준 입력으로 받은 값을 암호화해서 enc:... 출력
+
+공통 옵션:
+  --config 경로      설정 파일 경로 (기본값 tunnels.conf)
+  --json             JSON으로 출력
+
+헤드리스 모드 옵션:
+  --log-file 경로    로그 파일 경로 (기본값 tunnels.log)
+  --log-stderr       로그를 파일 대신 표준 에러로 출력
+
+status, up, down, restart, reload는 실행 중인 인스턴스의 제어 API(api.enabled: true)를 사용합니다.
+실행 중인 인스턴스가 없거나 제어 API가 비활성화되어 있으면 종료 코드 3으로 종료합니다.
+`
+
+// runCommand CLI 하위 명령 실행 (하위 명령이 아니면 false 반환 후 트레이 앱 실행)
+func runCommand(args []string) (bool, int) {
+	if len(args) == 0 {
+		return false, 0
+	}
+
+	var code int
+	switch args[0] {
+	case "encrypt":
+		attachParentConsole()
+		code = runEncrypt(args[1:])
+	case "status":
+		attachParentConsole()
+		code = runStatus(args[1:])
+	case "up", "down", "restart":
+		attachParentConsole()
+		code = runTunnelAction(args[0], args[1:])
+	case "reload":
+		attachParentConsole()
+		code = runReload(args[1:])
+	case "validate":
+		attachParentConsole()
+		code = runValidate(args[1:])
+	case "config":
+		attachParentConsole()
+		code = runConfig(args[1:])
+	case "--headless":
+		attachParentConsole()
+		code = runHeadless(args[1:])
+	case "help", "-h", "--help":
+		attachParentConsole()
+		fmt.Fprint(os.Stdout, usage)
+		return true, exitOK
+	default:
+		return false, 0
+	}
+
+	if code == exitUsage {
+		fmt.Fprint(os.Stderr, usage)
+	}
+	return true, code
+}
+
+// validateResult validate 명령 JSON 출력
+type validateResult struct {
+	Valid   bool                     `json:"valid"`
+	Errors  []config.ValidationError `json:"errors"` // 찾은 모든 문제 (설정 파일 줄 순서)
+	Tunnels []validateTunnelResult   `json:"tunnels"`
+}
+
+// validateTunnelResult 터널 하나의 검사 결과
+type validateTunnelResult struct {
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+	Valid   bool   `json:"valid"`
+}
+
+// runValidate 설정 파일 검사 (파싱, 알 수 없는 키, 터널별 설정, 중복된 이름/포트, 키 파일, 제어 API 설정)
+//
+// 사용법: tunnels validate [--json] [--config 설정파일]
+func runValidate(args []string) int {
+	fs, opts := newCommandFlags("validate", false)
+	if positional, err := parseCommandArgs(fs, args); err != nil || len(positional) > 0 {
+		return exitUsage
+	}
+
+	result := validateResult{Valid: true, Errors: []config.ValidationError{}, Tunnels: []validateTunnelResult{}}
+	cfg, absConfigPath, err := loadCLIConfig(opts.configPath)
+	if err != nil {
+		result.Valid = false
+		result.Errors = append(result.Errors, config.ValidationError{Message: err.Error()})
+	} else {
+		var errs config.ValidationErrors
+		if err := cfg.Validate(); err != nil && errors.As(err, &errs) {
+			result.Valid = false
+			result.Errors = errs
+		}
+		invalid := make(map[string]bool)
+		for _, e := range result.Errors {
+			// 설정 파일 전체 문제(api, 전역 retry, 최상위의 알 수 없는 키)는 터널 결과에 반영하지 않음
+			if e.Tunnel != "" {
+				invalid[e.Tunnel] = true
+			}
+		}
+		for _, t := range cfg.Tunnels {
+			// 이름이 없는 터널은 이름 오류가 터널 없이 보고되므로 항상 문제 있음
+			valid := t.Name != "" && !invalid[t.Name]
+			result.Tunnels = append(result.Tunnels, validateTunnelResult{Name: t.Name, Enabled: t.Enabled, Valid: valid})
+		}
+	}
+
+	if opts.json {
+		printJSON(result)
+	} else {
+		for _, e := range result.Errors {
+			if e.Line > 0 {
+				fmt.Printf("✗ %s:%s\n", filepath.Base(absConfigPath), e)
+			} else {
+				fmt.Printf("✗ %s\n", e)
+			}
+		}
+		if len(result.Errors) > 0 && len(result.Tunnels) > 0 {
+			fmt.Println()
+		}
+		for _, t := range result.Tunnels {
+			state := ""
+			if !t.Enabled {
+				state = " (비활성화)"
+			}
+			mark := "✓"
+			if !t.Valid {
+				mark = "✗"
+			}
+			fmt.Printf("%s %s%s\n", mark, t.Name, state)
+		}
+		if result.Valid {
+			fmt.Println("설정 파일에 문제가 없습니다")
+		} else {
+			fmt.Printf("문제 %d개를 찾았습니다\n", len(result.Errors))
+		}
+	}
+
+	if !result.Valid {
+		return exitError
+	}
+	return exitOK
+}
+
+// runEncrypt 표준 입력으로 받은 값을 마스터 키로 암호화해서 "enc:..." 출력
+//
+// 사용법: tunnels encrypt [--config 설정파일]
+// 마스터 키는 설정 파일과 같은 디렉토리의 tunnels.key (없으면 생성)
+func runEncrypt(args []string) int {
+	fs, opts := newCommandFlags("encrypt", false)
+	if positional, err := parseCommandArgs(fs, args); err != nil || len(positional) > 0 {
+		return exitUsage
+	}
+	absConfigPath, err := filepath.Abs(opts.configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "설정 파일 경로 오류: %v\n", err)
+		return 1
+	}
+
+	// 셸 기록에 남지 않도록 명령줄 인자 대신 표준 입력으로 받음
+	fmt.Fprint(os.Stderr, "암호화할 값 입력: ")
+	value, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && err != io.EOF {
+		fmt.Fprintf(os.Stderr, "\n입력 읽기 실패: %v\n", err)
+		return 1
+	}
+	value = strings.TrimRight(value, "\r\n")
+	if value == "" {
+		fmt.Fprintln(os.Stderr, "\n암호화할 값이 비어 있습니다")
+		return 1
+	}
+
+	encrypted, err := config.EncryptSecret(value, absConfigPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "\n암호화 실패: %v\n", err)
+		return 1
+	}
+
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stdout, encrypted)
+	return 0
+}
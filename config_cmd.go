@@ -0,0 +1,292 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"tunnels/internal/config"
+)
+
+// runConfig 설정 파일 관리 명령 (백업 목록, 복원, ssh_config 가져오기)
+//
+// 사용법: tunnels config backups|restore [번호|백업파일] [--config 설정파일]
+// 사용법: tunnels config import [Host...] [--ssh-config 경로] [--alias] [--enable] [--dry-run] [--config 설정파일]
+func runConfig(args []string) int {
+	if len(args) == 0 {
+		return exitUsage
+	}
+
+	switch args[0] {
+	case "backups":
+		return runConfigBackups(args[1:])
+	case "restore":
+		return runConfigRestore(args[1:])
+	case "import":
+		return runConfigImport(args[1:])
+	default:
+		return exitUsage
+	}
+}
+
+// runConfigBackups 설정 파일 백업 목록 출력 (1이 가장 최근)
+func runConfigBackups(args []string) int {
+	fs, opts := newCommandFlags("config backups", false)
+	if positional, err := parseCommandArgs(fs, args); err != nil || len(positional) > 0 {
+		return exitUsage
+	}
+
+	absConfigPath, err := filepath.Abs(opts.configPath)
+	if err != nil {
+		return printCommandError(fmt.Errorf("설정 파일 경로 오류: %v", err))
+	}
+	backups, err := config.ListConfigBackups(absConfigPath)
+	if err != nil {
+		return printCommandError(err)
+	}
+	if opts.json {
+		if backups == nil {
+			backups = []config.ConfigBackup{}
+		}
+		return printJSON(backups)
+	}
+	if len(backups) == 0 {
+		fmt.Println("백업이 없습니다")
+		return exitOK
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "#\tTIME\tSIZE\tFILE")
+	for i, backup := range backups {
+		fmt.Fprintf(tw, "%d\t%s\t%d\t%s\n", i+1, backup.Time.Format("2006-01-02 15:04:05"), backup.Size, backup.Path)
+	}
+	tw.Flush()
+	return exitOK
+}
+
+// runConfigRestore 백업으로 설정 파일 복원 (번호나 파일을 지정하지 않으면 가장 최근 백업)
+// 복원 전 설정 파일도 백업되므로 다시 되돌릴 수 있음
+func runConfigRestore(args []string) int {
+	fs, opts := newCommandFlags("config restore", false)
+	positional, err := parseCommandArgs(fs, args)
+	if err != nil || len(positional) > 1 {
+		return exitUsage
+	}
+
+	absConfigPath, err := filepath.Abs(opts.configPath)
+	if err != nil {
+		return printCommandError(fmt.Errorf("설정 파일 경로 오류: %v", err))
+	}
+	backups, err := config.ListConfigBackups(absConfigPath)
+	if err != nil {
+		return printCommandError(err)
+	}
+
+	backupPath := ""
+	selector := "1"
+	if len(positional) == 1 {
+		selector = positional[0]
+	}
+	if n, err := strconv.Atoi(selector); err == nil {
+		if n < 1 || n > len(backups) {
+			return printCommandError(fmt.Errorf("백업 %d번이 없습니다 (tunnels config backups로 목록 확인)", n))
+		}
+		backupPath = backups[n-1].Path
+	} else {
+		backupPath = selector
+	}
+
+	if err := config.RestoreConfigBackup(absConfigPath, backupPath); err != nil {
+		return printCommandError(err)
+	}
+	fmt.Printf("설정 파일 복원 완료: %s -> %s\n", backupPath, absConfigPath)
+	fmt.Println("실행 중인 인스턴스는 바뀐 설정 파일을 자동으로 다시 로드합니다")
+	return exitOK
+}
+
+// importResult config import 명령 JSON 출력
+type importResult struct {
+	Added   []importedTunnel `json:"added"`
+	Skipped []importSkipped  `json:"skipped"`
+	Saved   bool             `json:"saved"` // --dry-run이거나 추가한 터널이 없으면 false
+}
+
+// importedTunnel 설정 파일에 추가한 터널
+type importedTunnel struct {
+	Name       string   `json:"name"`
+	Connection string   `json:"connection"` // 사용자@호스트:포트 (--alias면 ssh_config 별칭)
+	Forwards   []string `json:"forwards"`
+	Warnings   []string `json:"warnings,omitempty"` // 변환하지 못하고 건너뛴 ssh_config 설정
+}
+
+// importSkipped 가져오지 않은 Host와 이유
+type importSkipped struct {
+	Name     string   `json:"name"`
+	Reason   string   `json:"reason"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// runConfigImport ssh_config(~/.ssh/config)의 포워딩 설정을 터널로 가져오기
+//
+// Host를 지정하지 않으면 LocalForward, RemoteForward, DynamicForward가 있는 모든 Host를 가져오고,
+// 지정하면 해당 Host만 가져옴 (db-* 같은 와일드카드 가능)
+// 가져온 터널은 비활성화 상태로 추가되며(--enable이면 활성화), 같은 이름의 터널이 있으면 건너뜀
+// --alias면 접속 정보를 복사하지 않고 ssh_alias로 Host 별칭을 참조
+func runConfigImport(args []string) int {
+	fs, opts := newCommandFlags("config import", false)
+	sshConfigPath := fs.String("ssh-config", config.DefaultSSHConfigPath(), "OpenSSH 설정 파일 경로")
+	useAlias := fs.Bool("alias", false, "접속 정보를 복사하지 않고 ssh_alias로 Host 별칭 참조")
+	enable := fs.Bool("enable", false, "가져온 터널을 활성화 상태로 추가")
+	dryRun := fs.Bool("dry-run", false, "설정 파일을 바꾸지 않고 가져올 터널만 출력")
+	patterns, err := parseCommandArgs(fs, args)
+	if err != nil {
+		return exitUsage
+	}
+	// ssh와 설정 검사는 기본 위치의 ssh_config만 읽으므로 다른 파일의 Host 별칭은 해석할 수 없음
+	if *useAlias && !sameFilePath(*sshConfigPath, config.DefaultSSHConfigPath()) {
+		fmt.Fprintf(os.Stderr, "--alias는 기본 ssh 설정 파일(%s)에서만 사용할 수 있습니다\n", config.DefaultSSHConfigPath())
+		return exitUsage
+	}
+
+	cfg, absConfigPath, err := loadCLIConfig(opts.configPath)
+	if err != nil {
+		return printCommandError(err)
+	}
+	sshConfig, err := config.LoadSSHConfig(*sshConfigPath)
+	if err != nil {
+		return printCommandError(err)
+	}
+	editor, err := config.OpenEditor(absConfigPath)
+	if err != nil {
+		return printCommandError(err)
+	}
+
+	existing := make(map[string]bool, len(cfg.Tunnels))
+	for _, t := range cfg.Tunnels {
+		existing[t.Name] = true
+	}
+
+	result := importResult{Added: []importedTunnel{}, Skipped: []importSkipped{}}
+	matched := make([]bool, len(patterns))
+	for _, alias := range sshConfig.Hosts() {
+		if len(patterns) > 0 && !matchImportPatterns(patterns, alias, matched) {
+			continue
+		}
+
+		importTunnel := sshConfig.ImportTunnel
+		if *useAlias {
+			importTunnel = sshConfig.ImportAliasTunnel
+		}
+		tunnel, warnings, err := importTunnel(alias)
+		if err != nil {
+			// Host를 지정하지 않았으면 포워딩이 없는 일반 Host는 조용히 건너뜀
+			if len(patterns) > 0 || !errors.Is(err, config.ErrNoSSHForwards) {
+				result.Skipped = append(result.Skipped, importSkipped{Name: alias, Reason: err.Error(), Warnings: warnings})
+			}
+			continue
+		}
+		if existing[alias] {
+			result.Skipped = append(result.Skipped, importSkipped{Name: alias, Reason: "같은 이름의 터널이 이미 있습니다"})
+			continue
+		}
+
+		tunnel.Enabled = *enable
+		if err := editor.AddTunnel(tunnel); err != nil {
+			result.Skipped = append(result.Skipped, importSkipped{Name: alias, Reason: err.Error()})
+			continue
+		}
+		existing[alias] = true
+
+		imported := importedTunnel{
+			Name:       alias,
+			Connection: fmt.Sprintf("%s@%s:%d", tunnel.SSHUser, tunnel.SSHHost, tunnel.SSHPort),
+			Warnings:   warnings,
+		}
+		if tunnel.SSHAlias != "" {
+			imported.Connection = "ssh_config " + tunnel.SSHAlias
+		}
+		for _, f := range tunnel.GetForwards() {
+			imported.Forwards = append(imported.Forwards, f.Label())
+		}
+		result.Added = append(result.Added, imported)
+	}
+	for i, pattern := range patterns {
+		if !matched[i] {
+			result.Skipped = append(result.Skipped, importSkipped{Name: pattern, Reason: "ssh 설정에 없는 Host입니다"})
+		}
+	}
+
+	if len(result.Added) > 0 && !*dryRun {
+		if err := editor.Save(); err != nil {
+			return printCommandError(err)
+		}
+		result.Saved = true
+	}
+
+	if opts.json {
+		printJSON(result)
+	} else {
+		printImportResult(result, absConfigPath, *enable)
+	}
+
+	if len(result.Added) == 0 && len(result.Skipped) > 0 {
+		return exitError
+	}
+	return exitOK
+}
+
+// sameFilePath 두 경로가 같은 파일을 가리키는지 비교 (상대 경로는 현재 디렉토리 기준)
+func sameFilePath(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	if errA != nil || errB != nil {
+		return filepath.Clean(a) == filepath.Clean(b)
+	}
+	return absA == absB
+}
+
+// matchImportPatterns 별칭이 명령줄에서 지정한 Host 패턴 중 하나와 일치하는지 확인 (일치한 패턴 표시)
+func matchImportPatterns(patterns []string, alias string, matched []bool) bool {
+	found := false
+	for i, pattern := range patterns {
+		if ok, _ := path.Match(pattern, alias); ok {
+			matched[i] = true
+			found = true
+		}
+	}
+	return found
+}
+
+// printImportResult config import 결과 출력
+func printImportResult(result importResult, configPath string, enabled bool) {
+	for _, t := range result.Added {
+		fmt.Printf("+ %s (%s, %s)\n", t.Name, t.Connection, strings.Join(t.Forwards, ", "))
+		for _, w := range t.Warnings {
+			fmt.Printf("  경고: %s\n", w)
+		}
+	}
+	for _, s := range result.Skipped {
+		fmt.Printf("- %s: %s\n", s.Name, s.Reason)
+		for _, w := range s.Warnings {
+			fmt.Printf("  경고: %s\n", w)
+		}
+	}
+
+	switch {
+	case len(result.Added) == 0:
+		if len(result.Skipped) == 0 {
+			fmt.Println("가져올 터널이 없습니다 (포워딩 설정이 있는 Host가 없음)")
+		}
+	case !result.Saved:
+		fmt.Printf("터널 %d개를 가져올 수 있습니다 (--dry-run이라 저장하지 않음)\n", len(result.Added))
+	case enabled:
+		fmt.Printf("터널 %d개를 %s에 추가했습니다\n", len(result.Added), configPath)
+	default:
+		fmt.Printf("터널 %d개를 %s에 비활성화 상태로 추가했습니다 (트레이 메뉴의 Enable이나 enabled: true로 활성화)\n", len(result.Added), configPath)
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !windows
+
+package main
+
+// hideConsoleWindow Windows 이외의 OS에서는 숨길 콘솔 창이 없음
+func hideConsoleWindow() {}
+
+// attachParentConsole Windows 이외의 OS에서는 CLI 출력이 그대로 터미널에 표시됨
+func attachParentConsole() {}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+// hideConsoleWindow Windows에서 콘솔 창 숨기기
+func hideConsoleWindow() {
+	kernel32 := syscall.NewLazyDLL("kernel32.dll")
+	user32 := syscall.NewLazyDLL("user32.dll")
+
+	procGetConsoleWindow := kernel32.NewProc("GetConsoleWindow")
+	procShowWindow := user32.NewProc("ShowWindow")
+	procFreeConsole := kernel32.NewProc("FreeConsole")
+
+	// 콘솔 창 숨기기
+	consoleWindow, _, _ := procGetConsoleWindow.Call()
+	if consoleWindow != 0 {
+		procShowWindow.Call(consoleWindow, 0) // SW_HIDE = 0
+	}
+
+	// 콘솔 해제 (더 강력한 방법)
+	procFreeConsole.Call()
+}
+
+// attachParentConsole CLI 하위 명령 실행 시 부모 콘솔(cmd, PowerShell)에 연결
+//
+// GUI 애플리케이션(-H windowsgui)으로 빌드되어 콘솔이 없으므로, 리다이렉트되지 않은
+// 표준 입출력을 부모 프로세스의 콘솔로 연결해서 출력이 보이도록 함
+func attachParentConsole() {
+	kernel32 := syscall.NewLazyDLL("kernel32.dll")
+	procAttachConsole := kernel32.NewProc("AttachConsole")
+
+	const attachParentProcess = ^uintptr(0) // ATTACH_PARENT_PROCESS (-1)
+	if r, _, _ := procAttachConsole.Call(attachParentProcess); r == 0 {
+		return
+	}
+
+	if !hasStdHandle(syscall.STD_INPUT_HANDLE) {
+		if f, err := os.OpenFile("CONIN$", os.O_RDWR, 0); err == nil {
+			os.Stdin = f
+		}
+	}
+	if !hasStdHandle(syscall.STD_OUTPUT_HANDLE) {
+		if f, err := os.OpenFile("CONOUT$", os.O_RDWR, 0); err == nil {
+			os.Stdout = f
+		}
+	}
+	if !hasStdHandle(syscall.STD_ERROR_HANDLE) {
+		if f, err := os.OpenFile("CONOUT$", os.O_RDWR, 0); err == nil {
+			os.Stderr = f
+		}
+	}
+}
+
+// hasStdHandle 표준 핸들이 리다이렉트 등으로 이미 연결되어 있는지 확인
+func hasStdHandle(id int) bool {
+	h, err := syscall.GetStdHandle(id)
+	return err == nil && h != 0 && h != syscall.InvalidHandle
+}
@@ -0,0 +1,326 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"tunnels/internal/api"
+	"tunnels/internal/config"
+	"tunnels/internal/tunnel"
+)
+
+// defaultWait up/restart에서 연결될 때까지 기다리는 기본 시간 (초)
+const defaultWait = 30
+
+// commandFlags 하위 명령 공통 옵션
+type commandFlags struct {
+	configPath string
+	json       bool
+	wait       int
+}
+
+// newCommandFlags 하위 명령 옵션 파서 생성 (withWait이면 --wait 포함)
+func newCommandFlags(name string, withWait bool) (*flag.FlagSet, *commandFlags) {
+	opts := &commandFlags{}
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	fs.StringVar(&opts.configPath, "config", "tunnels.conf", "설정 파일 경로")
+	fs.BoolVar(&opts.json, "json", false, "JSON으로 출력")
+	if withWait {
+		fs.IntVar(&opts.wait, "wait", defaultWait, "연결될 때까지 기다릴 최대 시간 (초, 0이면 기다리지 않음)")
+	}
+	return fs, opts
+}
+
+// parseCommandArgs 옵션과 위치 인자를 순서에 관계없이 파싱 (tunnels up db --json 형태 지원)
+func parseCommandArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// loadCLIConfig CLI용 설정 로드 (설정 파일이 없으면 기본 설정을 만들지 않고 오류)
+func loadCLIConfig(configPath string) (*config.Config, string, error) {
+	absConfigPath, err := filepath.Abs(configPath)
+	if err != nil {
+		return nil, "", fmt.Errorf("설정 파일 경로 오류: %v", err)
+	}
+	if _, err := os.Stat(absConfigPath); err != nil {
+		return nil, "", fmt.Errorf("설정 파일을 찾을 수 없습니다: %s", absConfigPath)
+	}
+	cfg, err := config.LoadConfig(absConfigPath)
+	if err != nil {
+		return nil, "", err
+	}
+	return cfg, absConfigPath, nil
+}
+
+// newControlClient 설정 파일의 api 설정으로 실행 중인 인스턴스에 연결할 클라이언트 생성
+func newControlClient(configPath string) (*api.Client, error) {
+	cfg, absConfigPath, err := loadCLIConfig(configPath)
+	if err != nil {
+		return nil, err
+	}
+	return api.NewClient(cfg.API, absConfigPath)
+}
+
+// runStatus 터널 상태 출력
+//
+// 사용법: tunnels status [이름] [--json] [--config 설정파일]
+func runStatus(args []string) int {
+	fs, opts := newCommandFlags("status", false)
+	positional, err := parseCommandArgs(fs, args)
+	if err != nil || len(positional) > 1 {
+		return exitUsage
+	}
+
+	client, err := newControlClient(opts.configPath)
+	if err != nil {
+		return printCommandError(err)
+	}
+
+	if len(positional) == 1 {
+		info, err := client.Get(positional[0])
+		if err != nil {
+			return printCommandError(err)
+		}
+		if opts.json {
+			return printJSON(info)
+		}
+		printTunnelDetail(os.Stdout, info)
+		return exitOK
+	}
+
+	infos, err := client.List()
+	if err != nil {
+		return printCommandError(err)
+	}
+	if opts.json {
+		return printJSON(infos)
+	}
+	printTunnelTable(os.Stdout, infos)
+	return exitOK
+}
+
+// runTunnelAction 터널 하나 시작/중지/재시작 (up, down, restart)
+//
+// 사용법: tunnels up|down|restart <이름> [--wait 초] [--json] [--config 설정파일]
+func runTunnelAction(action string, args []string) int {
+	fs, opts := newCommandFlags(action, action != "down")
+	positional, err := parseCommandArgs(fs, args)
+	if err != nil || len(positional) != 1 || opts.wait < 0 {
+		fmt.Fprintf(os.Stderr, "사용법: tunnels %s <터널 이름>\n", action)
+		return exitUsage
+	}
+	name := positional[0]
+
+	client, err := newControlClient(opts.configPath)
+	if err != nil {
+		return printCommandError(err)
+	}
+
+	wait := time.Duration(opts.wait) * time.Second
+	var info api.TunnelInfo
+	switch action {
+	case "up":
+		info, err = client.Start(name, wait)
+	case "down":
+		info, err = client.Stop(name)
+	default:
+		info, err = client.Restart(name, wait)
+	}
+	if err != nil {
+		return printCommandError(err)
+	}
+
+	if opts.json {
+		return printJSON(info)
+	}
+	printTunnelTable(os.Stdout, []api.TunnelInfo{info})
+	return exitOK
+}
+
+// runReload 실행 중인 인스턴스에 설정 다시 로드 요청
+//
+// 사용법: tunnels reload [--json] [--config 설정파일]
+func runReload(args []string) int {
+	fs, opts := newCommandFlags("reload", false)
+	if positional, err := parseCommandArgs(fs, args); err != nil || len(positional) > 0 {
+		return exitUsage
+	}
+
+	client, err := newControlClient(opts.configPath)
+	if err != nil {
+		return printCommandError(err)
+	}
+	result, err := client.Reload()
+	if err != nil {
+		return printCommandError(err)
+	}
+
+	if opts.json {
+		return printJSON(result)
+	}
+	for _, group := range []struct {
+		label string
+		names []string
+	}{
+		{"추가", result.Added},
+		{"제거", result.Removed},
+		{"변경", result.Changed},
+		{"유지", result.Unchanged},
+		{"건너뜀", result.Skipped},
+	} {
+		if len(group.names) > 0 {
+			fmt.Printf("%s: %s\n", group.label, strings.Join(group.names, ", "))
+		}
+	}
+	fmt.Printf("설정 다시 로드 완료 (추가 %d, 제거 %d, 변경 %d, 유지 %d)\n",
+		len(result.Added), len(result.Removed), len(result.Changed), len(result.Unchanged))
+	return exitOK
+}
+
+// printTunnelTable 터널 상태 표 출력
+func printTunnelTable(w io.Writer, infos []api.TunnelInfo) {
+	if len(infos) == 0 {
+		fmt.Fprintln(w, "활성화된 터널이 없습니다")
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tSTATUS\tPORTS\tDETAIL")
+	for _, info := range infos {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", info.Name, statusLabel(info), portsLabel(info), detailLabel(info))
+	}
+	tw.Flush()
+}
+
+// printTunnelDetail 터널 하나의 상세 상태 출력 (포워딩별 상태, 최근 ssh 출력 포함)
+func printTunnelDetail(w io.Writer, info api.TunnelInfo) {
+	printTunnelTable(w, []api.TunnelInfo{info})
+	fmt.Fprintf(w, "\n연결: %s\n", info.Connection)
+	if info.LastCheck != nil {
+		fmt.Fprintf(w, "마지막 확인: %s\n", info.LastCheck.Local().Format("2006-01-02 15:04:05"))
+	}
+	if info.ExitCode != nil && info.ExitTime != nil {
+		fmt.Fprintf(w, "마지막 종료: 종료 코드 %d (%s)\n", *info.ExitCode, info.ExitTime.Local().Format("2006-01-02 15:04:05"))
+	}
+	if len(info.Forwards) > 1 {
+		fmt.Fprintln(w, "\n포워딩:")
+		for _, f := range info.Forwards {
+			line := fmt.Sprintf("  %s  %s", f.Label, strings.ToUpper(f.Status))
+			if f.LastError != "" {
+				line += "  " + f.LastError
+			}
+			fmt.Fprintln(w, line)
+		}
+	}
+	if len(info.Output) > 0 {
+		fmt.Fprintln(w, "\nssh 출력:")
+		for _, line := range info.Output {
+			fmt.Fprintf(w, "  %s\n", line)
+		}
+	}
+}
+
+// statusLabel 표에 표시할 상태 (오류나 프로브 실패면 실패 원인, 일부 포워딩만 실패하면 PARTIAL)
+func statusLabel(info api.TunnelInfo) string {
+	switch tunnel.Status(info.Status) {
+	case tunnel.StatusError, tunnel.StatusDegraded:
+		return tunnel.FailureReason(info.Failure).Label()
+	case tunnel.StatusConnected:
+		healthy := 0
+		for _, f := range info.Forwards {
+			if tunnel.Status(f.Status) == tunnel.StatusConnected {
+				healthy++
+			}
+		}
+		if healthy < len(info.Forwards) {
+			return fmt.Sprintf("PARTIAL %d/%d", healthy, len(info.Forwards))
+		}
+	}
+	return strings.ToUpper(info.Status)
+}
+
+// portsLabel 포트 목록 (원격 포워딩은 R:, SOCKS5 프록시는 D: 표시)
+func portsLabel(info api.TunnelInfo) string {
+	ports := make([]string, len(info.Forwards))
+	for i, f := range info.Forwards {
+		switch f.Type {
+		case config.TypeRemote:
+			ports[i] = fmt.Sprintf("R:%d", f.RemotePort)
+		case config.TypeDynamic:
+			ports[i] = fmt.Sprintf("D:%d", f.LocalPort)
+		default:
+			ports[i] = fmt.Sprintf("%d", f.LocalPort)
+		}
+	}
+	return strings.Join(ports, ",")
+}
+
+// detailLabel 오류 메시지와 재시도 예정 시간, 또는 상태 확인 지연 시간
+func detailLabel(info api.TunnelInfo) string {
+	if tunnel.Status(info.Status) == tunnel.StatusDegraded {
+		return info.LastError
+	}
+	if tunnel.Status(info.Status) == tunnel.StatusError {
+		detail := info.LastError
+		if info.NextRetry != nil {
+			detail += fmt.Sprintf(" (retry %s)", info.NextRetry.Local().Format("15:04:05"))
+		}
+		return detail
+	}
+	if tunnel.Status(info.Status) == tunnel.StatusConnected {
+		latencies := make([]string, 0, len(info.Forwards))
+		for _, f := range info.Forwards {
+			if tunnel.Status(f.Status) == tunnel.StatusConnected {
+				latency := time.Duration(f.LatencyMS * float64(time.Millisecond)).Round(time.Millisecond)
+				latencies = append(latencies, latency.String())
+			}
+		}
+		return strings.Join(latencies, ", ")
+	}
+	return ""
+}
+
+// printJSON JSON 출력
+func printJSON(v interface{}) int {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(v); err != nil {
+		fmt.Fprintf(os.Stderr, "JSON 출력 실패: %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
+
+// printCommandError 오류 출력 (터널 작업 실패면 현재 상태도 함께 출력)
+func printCommandError(err error) int {
+	fmt.Fprintf(os.Stderr, "오류: %v\n", err)
+	if errors.Is(err, api.ErrNotRunning) {
+		fmt.Fprintln(os.Stderr, "tunnels가 실행 중인지, 설정 파일에서 api.enabled: true로 제어 API를 활성화했는지 확인하세요")
+		return exitNotRunning
+	}
+	var apiErr *api.Error
+	if errors.As(err, &apiErr) && apiErr.Tunnel != nil {
+		printTunnelTable(os.Stderr, []api.TunnelInfo{*apiErr.Tunnel})
+	}
+	return exitError
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"tunnels/internal/service"
+)
+
+// runHeadless 시스템 트레이 없이 터널 관리 (서버, 컨테이너, systemd 서비스용)
+//
+// 사용법: tunnels --headless [설정파일] [--config 설정파일] [--log-file 경로] [--log-stderr]
+//
+// SIGINT/SIGTERM을 받으면 모든 터널을 중지하고 0으로 종료하며, SIGHUP을 받으면 설정을 다시 로드합니다.
+// 시작할 때 설정 파일을 읽을 수 없으면 1로 종료합니다.
+func runHeadless(args []string) int {
+	fs := flag.NewFlagSet("--headless", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	configPath := fs.String("config", "tunnels.conf", "설정 파일 경로")
+	logPath := fs.String("log-file", "tunnels.log", "로그 파일 경로")
+	logStderr := fs.Bool("log-stderr", false, "로그를 파일 대신 표준 에러로 출력")
+	positional, err := parseCommandArgs(fs, args)
+	if err != nil || len(positional) > 1 {
+		return exitUsage
+	}
+	if len(positional) == 1 {
+		*configPath = positional[0]
+	}
+
+	// 트레이 앱과 달리 설정 파일이 없으면 기본 설정을 만들지 않고 종료
+	absConfigPath, err := filepath.Abs(*configPath)
+	if err != nil {
+		return printCommandError(fmt.Errorf("설정 파일 경로 오류: %v", err))
+	}
+	if _, err := os.Stat(absConfigPath); err != nil {
+		return printCommandError(fmt.Errorf("설정 파일을 찾을 수 없습니다: %s", absConfigPath))
+	}
+
+	if !*logStderr {
+		logFile, err := setupLogFile(*logPath)
+		if err != nil {
+			return printCommandError(fmt.Errorf("로그 파일 열기 실패: %v", err))
+		}
+		defer logFile.Close()
+	}
+
+	// 시작하는 동안 받은 시그널도 처리하도록 서비스 시작 전에 등록
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+	defer signal.Stop(sigChan)
+
+	log.Printf("헤드리스 모드 시작 (설정 파일: %s)", absConfigPath)
+	svc := service.New(absConfigPath)
+	if err := svc.Start(); err != nil {
+		svc.Stop()
+		if !*logStderr {
+			fmt.Fprintf(os.Stderr, "오류: %v\n", err)
+		}
+		return exitError
+	}
+
+	for sig := range sigChan {
+		if sig == syscall.SIGHUP {
+			log.Println("SIGHUP 수신 - 설정 다시 로드")
+			if _, err := svc.Manager().Reload(); err != nil {
+				log.Printf("설정 다시 로드 실패 (이전 설정으로 계속 동작): %v", err)
+			}
+			continue
+		}
+
+		log.Printf("시그널 수신 (%v) - 종료 중...", sig)
+		// 종료하는 동안 한 번 더 Ctrl+C를 누르면 기본 동작으로 즉시 종료
+		signal.Stop(sigChan)
+		break
+	}
+
+	svc.Stop()
+	log.Println("헤드리스 모드 종료 완료")
+	return exitOK
+}
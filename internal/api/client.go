@@ -0,0 +1,165 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"tunnels/internal/config"
+)
+
+// clientTimeout wait 없는 요청의 기본 타임아웃
+const clientTimeout = 30 * time.Second
+
+// ErrNotRunning 실행 중인 인스턴스에 연결할 수 없음 (인스턴스가 없거나 제어 API가 비활성화됨)
+var ErrNotRunning = errors.New("실행 중인 인스턴스가 없습니다")
+
+// Error 제어 API가 돌려준 오류 응답
+type Error struct {
+	StatusCode int
+	Message    string
+	Tunnel     *TunnelInfo // 터널 작업 중 실패한 경우 현재 상태
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Client 실행 중인 인스턴스의 제어 API 클라이언트 (CLI용)
+type Client struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+// NewClient 설정 파일의 api 설정으로 클라이언트 생성
+func NewClient(cfg config.APIConfig, configPath string) (*Client, error) {
+	if !cfg.Enabled {
+		return nil, fmt.Errorf("%w (%s에서 제어 API가 비활성화되어 있습니다, api.enabled: true로 설정하세요)", ErrNotRunning, configPath)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("제어 API 설정 오류: %v", err)
+	}
+	token, err := cfg.ResolveAPIToken(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := &http.Transport{Proxy: nil}
+	baseURL := ""
+	network, address := cfg.GetListen()
+	if network == "unix" {
+		// 호스트 이름은 쓰이지 않고 항상 소켓으로 연결
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", address)
+		}
+		baseURL = "http://tunnels"
+	} else {
+		baseURL = "http://" + address
+	}
+
+	return &Client{
+		baseURL: baseURL,
+		token:   token,
+		http:    &http.Client{Transport: transport},
+	}, nil
+}
+
+// List 모든 터널 상태
+func (c *Client) List() ([]TunnelInfo, error) {
+	var infos []TunnelInfo
+	err := c.do(http.MethodGet, tunnelsPath, 0, &infos)
+	return infos, err
+}
+
+// Get 터널 하나의 상태
+func (c *Client) Get(name string) (TunnelInfo, error) {
+	var info TunnelInfo
+	err := c.do(http.MethodGet, tunnelPath(name, ""), 0, &info)
+	return info, err
+}
+
+// Start 터널 시작 (wait가 0보다 크면 연결될 때까지 대기)
+func (c *Client) Start(name string, wait time.Duration) (TunnelInfo, error) {
+	var info TunnelInfo
+	err := c.do(http.MethodPost, tunnelPath(name, "start"), wait, &info)
+	return info, err
+}
+
+// Stop 터널 중지
+func (c *Client) Stop(name string) (TunnelInfo, error) {
+	var info TunnelInfo
+	err := c.do(http.MethodPost, tunnelPath(name, "stop"), 0, &info)
+	return info, err
+}
+
+// Restart 터널 재시작 (wait가 0보다 크면 연결될 때까지 대기)
+func (c *Client) Restart(name string, wait time.Duration) (TunnelInfo, error) {
+	var info TunnelInfo
+	err := c.do(http.MethodPost, tunnelPath(name, "restart"), wait, &info)
+	return info, err
+}
+
+// Reload 설정 다시 로드
+func (c *Client) Reload() (ReloadResult, error) {
+	var result ReloadResult
+	err := c.do(http.MethodPost, reloadPath, 0, &result)
+	return result, err
+}
+
+// tunnelPath 터널별 API 경로 (이름은 경로 세그먼트로 인코딩)
+func tunnelPath(name, action string) string {
+	path := tunnelsPath + "/" + url.PathEscape(name)
+	if action != "" {
+		path += "/" + action
+	}
+	return path
+}
+
+// do 요청을 보내고 JSON 응답을 out에 디코딩 (오류 응답은 *Error로 반환)
+func (c *Client) do(method, path string, wait time.Duration, out interface{}) error {
+	if wait > 0 {
+		path += "?wait=" + strconv.Itoa(int(wait.Seconds()))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), clientTimeout+wait)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w (제어 API에 연결할 수 없습니다: %v)", ErrNotRunning, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 4*1024*1024))
+	if err != nil {
+		return fmt.Errorf("응답 읽기 실패: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp ErrorResponse
+		if err := json.Unmarshal(body, &errResp); err != nil || errResp.Error == "" {
+			return &Error{StatusCode: resp.StatusCode, Message: resp.Status}
+		}
+		return &Error{StatusCode: resp.StatusCode, Message: errResp.Error, Tunnel: errResp.Tunnel}
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("응답 형식 오류: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+//go:build !windows
+
+package api
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+)
+
+// listenUnix 소유자만 접근할 수 있는 unix 소켓 생성 (0600)
+// 소켓 파일은 umask에 따른 권한으로 만들어지므로 리슨하는 동안 umask를 0077로 바꿔서 Chmod 전에 다른 사용자가 접속할 수 있는 틈을 없앰
+func listenUnix(path string) (net.Listener, error) {
+	oldMask := syscall.Umask(0077)
+	listener, err := net.Listen("unix", path)
+	syscall.Umask(oldMask)
+	if err != nil {
+		return nil, err
+	}
+	// 권한을 확인할 수 없는 소켓으로는 API를 열지 않음
+	if err := os.Chmod(path, 0600); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("unix 소켓 권한 설정 실패: %v", err)
+	}
+	return listener, nil
+}
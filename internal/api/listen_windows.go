@@ -0,0 +1,11 @@
+package api
+
+import (
+	"fmt"
+	"net"
+)
+
+// listenUnix Windows는 unix 소켓 접근을 파일 권한으로 제한할 수 없으므로 지원하지 않음 (APIConfig.Validate에서 먼저 거부)
+func listenUnix(path string) (net.Listener, error) {
+	return nil, fmt.Errorf("Windows에서는 unix 소켓을 지원하지 않습니다: %s", path)
+}
@@ -0,0 +1,361 @@
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"tunnels/internal/config"
+	"tunnels/internal/manager"
+	"tunnels/internal/tunnel"
+)
+
+// 제어 API 경로
+const (
+	tunnelsPath = "/api/tunnels"
+	reloadPath  = "/api/reload"
+	actionsPath = "/api/actions" // 전체 터널 작업 (터널 이름과 겹치지 않도록 /api/tunnels 밖에 둠)
+)
+
+// maxWait wait 파라미터 최대값 (초)
+const maxWait = 300
+
+// waitCheckInterval wait 중 연결 상태 확인 간격
+const waitCheckInterval = 1 * time.Second
+
+// Server 로컬 제어 API 서버
+type Server struct {
+	manager    *manager.Manager
+	token      string
+	server     *http.Server
+	socketPath string // unix 소켓으로 연 경우 종료 시 삭제할 경로
+}
+
+// NewServer 제어 API 서버 생성 (Handler로 요청 처리, 리슨은 Start에서)
+func NewServer(m *manager.Manager, token string) *Server {
+	s := &Server{manager: m, token: token}
+	s.server = &http.Server{
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return s
+}
+
+// Start 설정에 따라 제어 API 서버 시작 (127.0.0.1 또는 unix 소켓)
+func Start(m *manager.Manager, cfg config.APIConfig, configPath string) (*Server, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("제어 API 설정 오류: %v", err)
+	}
+	token, err := cfg.ResolveAPIToken(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("제어 API 토큰 준비 실패: %v", err)
+	}
+
+	network, address := cfg.GetListen()
+	var listener net.Listener
+	if network == "unix" {
+		// 이전 실행에서 남은 소켓 파일 정리
+		os.Remove(address)
+		listener, err = listenUnix(address)
+	} else {
+		listener, err = net.Listen(network, address)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("제어 API 리슨 실패: %v", err)
+	}
+
+	s := NewServer(m, token)
+	if network == "unix" {
+		s.socketPath = address
+	}
+
+	go func() {
+		if err := s.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("제어 API 서버 오류: %v", err)
+		}
+	}()
+	log.Printf("제어 API 시작: %s %s", network, address)
+	return s, nil
+}
+
+// Close 제어 API 서버 종료
+func (s *Server) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := s.server.Shutdown(ctx)
+	if s.socketPath != "" {
+		os.Remove(s.socketPath)
+	}
+	return err
+}
+
+// Handler 토큰 인증을 거친 뒤 API 요청을 처리하는 핸들러
+//
+//	GET  /api/tunnels                      모든 터널 상태
+//	GET  /api/tunnels/{name}               터널 상태
+//	POST /api/tunnels/{name}/start?wait=N  터널 시작 (wait: 연결될 때까지 최대 N초 대기)
+//	POST /api/tunnels/{name}/stop          터널 중지
+//	POST /api/tunnels/{name}/restart?wait=N 터널 재시작
+//	POST /api/actions/start-all            모든 터널 시작
+//	POST /api/actions/stop-all             모든 터널 중지
+//	POST /api/reload                       설정 다시 로드
+func (s *Server) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.authorized(r) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, fmt.Errorf("인증 토큰이 올바르지 않습니다"), nil)
+			return
+		}
+
+		path := strings.TrimSuffix(r.URL.EscapedPath(), "/")
+		switch {
+		case path == reloadPath:
+			s.handleReload(w, r)
+		case path == tunnelsPath:
+			s.handleList(w, r)
+		case path == actionsPath+"/start-all":
+			s.handleAll(w, r, "start")
+		case path == actionsPath+"/stop-all":
+			s.handleAll(w, r, "stop")
+		case strings.HasPrefix(path, tunnelsPath+"/"):
+			s.handleTunnelPath(w, r, strings.TrimPrefix(path, tunnelsPath+"/"))
+		default:
+			writeError(w, http.StatusNotFound, fmt.Errorf("알 수 없는 경로: %s", r.URL.Path), nil)
+		}
+	})
+}
+
+// authorized Authorization: Bearer 토큰 확인
+func (s *Server) authorized(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && s.token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) == 1
+}
+
+// handleList 모든 터널 상태
+func (s *Server) handleList(w http.ResponseWriter, r *http.Request) {
+	if !requireMethod(w, r, http.MethodGet) {
+		return
+	}
+	writeJSON(w, http.StatusOK, s.tunnelInfos())
+}
+
+// handleReload 설정 다시 로드
+func (s *Server) handleReload(w http.ResponseWriter, r *http.Request) {
+	if !requireMethod(w, r, http.MethodPost) {
+		return
+	}
+	log.Printf("제어 API: 설정 다시 로드")
+	summary, err := s.manager.Reload()
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err, nil)
+		return
+	}
+	writeJSON(w, http.StatusOK, newReloadResult(summary))
+}
+
+// handleTunnelPath /api/tunnels/ 아래 경로 처리 (터널 상태 또는 터널별 작업)
+func (s *Server) handleTunnelPath(w http.ResponseWriter, r *http.Request, rest string) {
+	segments := strings.Split(rest, "/")
+	if len(segments) > 2 {
+		writeError(w, http.StatusNotFound, fmt.Errorf("알 수 없는 경로: %s", r.URL.Path), nil)
+		return
+	}
+
+	name, err := url.PathUnescape(segments[0])
+	if err != nil || name == "" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("유효하지 않은 터널 이름: %s", segments[0]), nil)
+		return
+	}
+
+	if len(segments) == 1 {
+		if !requireMethod(w, r, http.MethodGet) {
+			return
+		}
+		status, err := s.manager.GetTunnelStatus(name)
+		if err != nil {
+			writeManagerError(w, err, nil)
+			return
+		}
+		writeJSON(w, http.StatusOK, NewTunnelInfo(status))
+		return
+	}
+
+	if !requireMethod(w, r, http.MethodPost) {
+		return
+	}
+	s.handleAction(w, r, name, segments[1])
+}
+
+// handleAll 모든 터널 시작 또는 중지
+func (s *Server) handleAll(w http.ResponseWriter, r *http.Request, action string) {
+	if !requireMethod(w, r, http.MethodPost) {
+		return
+	}
+	log.Printf("제어 API: 모든 터널 %s", action)
+	var err error
+	if action == "start" {
+		err = s.manager.StartAll()
+	} else {
+		err = s.manager.StopAll()
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err, nil)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.tunnelInfos())
+}
+
+// handleAction 터널 하나에 대한 작업 (start, stop, restart)
+func (s *Server) handleAction(w http.ResponseWriter, r *http.Request, name, action string) {
+	wait, err := parseWait(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	log.Printf("제어 API: 터널 '%s' %s", name, action)
+	switch action {
+	case "start":
+		err = s.manager.StartTunnel(name)
+	case "stop":
+		err = s.manager.StopTunnel(name)
+		wait = 0
+	case "restart":
+		err = s.manager.RestartTunnel(name)
+	default:
+		writeError(w, http.StatusNotFound, fmt.Errorf("알 수 없는 작업: %s (start, stop 또는 restart)", action), nil)
+		return
+	}
+	if err != nil {
+		writeManagerError(w, err, s.tunnelInfo(name))
+		return
+	}
+
+	if wait > 0 {
+		if err := s.waitConnected(r.Context(), name, wait); err != nil {
+			writeError(w, http.StatusGatewayTimeout, err, s.tunnelInfo(name))
+			return
+		}
+	}
+
+	status, err := s.manager.GetTunnelStatus(name)
+	if err != nil {
+		writeManagerError(w, err, nil)
+		return
+	}
+	writeJSON(w, http.StatusOK, NewTunnelInfo(status))
+}
+
+// waitConnected 터널이 연결될 때까지 대기 (자동 재시작이 중단되거나 시간 초과 시 오류)
+// 재시작 중에는 잠시 disconnected 상태가 되므로 disconnected는 계속 기다림
+func (s *Server) waitConnected(ctx context.Context, name string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		status, err := s.manager.GetTunnelStatus(name)
+		if err != nil {
+			return err
+		}
+		switch {
+		case status.Status == tunnel.StatusConnected:
+			return nil
+		case status.Status == tunnel.StatusError && status.NextRetry.IsZero():
+			return fmt.Errorf("터널 연결 실패: %s", status.LastError)
+		}
+		if time.Now().After(deadline) {
+			// 프로브 실패는 다음 확인에서 복구될 수 있으므로 기다리다가 시간 초과 시 원인과 함께 보고
+			if status.Status == tunnel.StatusDegraded {
+				return fmt.Errorf("%s 안에 연결되지 않았습니다: %s", timeout, status.LastError)
+			}
+			return fmt.Errorf("%s 안에 연결되지 않았습니다", timeout)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(waitCheckInterval):
+		}
+		// 다음 모니터링 주기까지 기다리지 않도록 연결 중인 터널은 바로 확인
+		if status, err := s.manager.GetTunnelStatus(name); err == nil && status.Status == tunnel.StatusConnecting {
+			s.manager.CheckTunnel(name)
+		}
+	}
+}
+
+// parseWait wait 쿼리 파라미터 (초) 파싱
+func parseWait(r *http.Request) (time.Duration, error) {
+	value := r.URL.Query().Get("wait")
+	if value == "" {
+		return 0, nil
+	}
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds < 0 || seconds > maxWait {
+		return 0, fmt.Errorf("유효하지 않은 wait 값: %s (0~%d초)", value, maxWait)
+	}
+	return time.Duration(seconds) * time.Second, nil
+}
+
+// tunnelInfos 모든 터널 상태 JSON 응답
+func (s *Server) tunnelInfos() []TunnelInfo {
+	statuses := s.manager.GetTunnelStatuses()
+	infos := make([]TunnelInfo, len(statuses))
+	for i, status := range statuses {
+		infos[i] = NewTunnelInfo(status)
+	}
+	return infos
+}
+
+// tunnelInfo 터널 하나의 상태 JSON 응답 (없으면 nil)
+func (s *Server) tunnelInfo(name string) *TunnelInfo {
+	status, err := s.manager.GetTunnelStatus(name)
+	if err != nil {
+		return nil
+	}
+	info := NewTunnelInfo(status)
+	return &info
+}
+
+// requireMethod 허용된 HTTP 메서드인지 확인 (아니면 405 응답)
+func requireMethod(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method == method {
+		return true
+	}
+	w.Header().Set("Allow", method)
+	writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("%s 메서드만 사용할 수 있습니다", method), nil)
+	return false
+}
+
+// writeManagerError 매니저 오류 응답 (없는 터널은 404)
+func writeManagerError(w http.ResponseWriter, err error, info *TunnelInfo) {
+	if errors.Is(err, manager.ErrTunnelNotFound) {
+		writeError(w, http.StatusNotFound, err, nil)
+		return
+	}
+	writeError(w, http.StatusConflict, err, info)
+}
+
+// writeError 오류 JSON 응답
+func writeError(w http.ResponseWriter, code int, err error, info *TunnelInfo) {
+	writeJSON(w, code, ErrorResponse{Error: err.Error(), Tunnel: info})
+}
+
+// writeJSON JSON 응답
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(code)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(v); err != nil {
+		log.Printf("제어 API 응답 전송 실패: %v", err)
+	}
+}
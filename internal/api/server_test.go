@@ -0,0 +1,400 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"tunnels/internal/manager"
+	"tunnels/internal/sshtest"
+)
+
+const testToken = "test-token"
+
+// testEnv 테스트 SSH 서버에 연결하는 설정 파일, 매니저, API 서버
+type testEnv struct {
+	t          *testing.T
+	configPath string
+	sshPort    int
+	manager    *manager.Manager
+	server     *httptest.Server
+}
+
+// tunnelYAML 테스트 SSH 서버로 연결하는 native 터널 설정
+// (비동기로 시작하는 터널이 테스트 종료 후 임시 디렉토리에 known_hosts를 쓰지 않도록 호스트 키 확인은 끔)
+func (e *testEnv) tunnelYAML(name string, enabled bool) string {
+	return fmt.Sprintf(`  - name: %s
+    local_port: %d
+    remote_host: 127.0.0.1
+    remote_port: 80
+    ssh_host: 127.0.0.1
+    ssh_port: %d
+    ssh_user: %s
+    ssh_password: %s
+    transport: native
+    host_key_policy: "off"
+    enabled: %t
+`, name, sshtest.FreePort(e.t), e.sshPort, sshtest.User, sshtest.Password, enabled)
+}
+
+// writeConfig 터널 목록으로 설정 파일 작성
+func (e *testEnv) writeConfig(tunnels ...string) {
+	e.t.Helper()
+	data := "tunnels:\n" + strings.Join(tunnels, "")
+	if err := os.WriteFile(e.configPath, []byte(data), 0600); err != nil {
+		e.t.Fatalf("설정 파일 저장 실패: %v", err)
+	}
+}
+
+// newTestEnv web, db 두 터널로 매니저와 API 서버 시작
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+	e := &testEnv{
+		t:          t,
+		configPath: filepath.Join(t.TempDir(), "config.yaml"),
+		sshPort:    sshtest.NewServer(t).Port,
+	}
+	e.writeConfig(e.tunnelYAML("web", true), e.tunnelYAML("db", true))
+
+	e.manager = manager.NewManager(e.configPath)
+	if _, err := e.manager.LoadConfig(); err != nil {
+		t.Fatalf("설정 로드 실패: %v", err)
+	}
+	t.Cleanup(func() { e.manager.Shutdown() })
+
+	e.server = httptest.NewServer(NewServer(e.manager, testToken).Handler())
+	t.Cleanup(e.server.Close)
+	return e
+}
+
+// do 토큰을 붙여 요청하고 응답 코드와 본문 반환
+func (e *testEnv) do(method, path string) (*http.Response, []byte) {
+	e.t.Helper()
+	return e.doWithAuth(method, path, "Bearer "+testToken)
+}
+
+// doWithAuth Authorization 헤더를 지정해서 요청
+func (e *testEnv) doWithAuth(method, path, auth string) (*http.Response, []byte) {
+	e.t.Helper()
+	req, err := http.NewRequest(method, e.server.URL+path, nil)
+	if err != nil {
+		e.t.Fatalf("요청 생성 실패: %v", err)
+	}
+	if auth != "" {
+		req.Header.Set("Authorization", auth)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		e.t.Fatalf("%s %s 요청 실패: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	var body json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		e.t.Fatalf("%s %s 응답이 JSON이 아님: %v", method, path, err)
+	}
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+		e.t.Fatalf("%s %s Content-Type이 JSON이 아님: %s", method, path, ct)
+	}
+	return resp, body
+}
+
+// expect 응답 코드를 확인하고 본문을 v로 디코딩
+func (e *testEnv) expect(method, path string, code int, v interface{}) *http.Response {
+	e.t.Helper()
+	resp, body := e.do(method, path)
+	if resp.StatusCode != code {
+		e.t.Fatalf("%s %s 응답 코드 %d (기대값 %d): %s", method, path, resp.StatusCode, code, body)
+	}
+	if v != nil {
+		if err := json.Unmarshal(body, v); err != nil {
+			e.t.Fatalf("%s %s 응답 디코딩 실패: %v\n%s", method, path, err, body)
+		}
+	}
+	return resp
+}
+
+// expectError 오류 응답 코드와 error 필드 확인
+func (e *testEnv) expectError(method, path string, code int) ErrorResponse {
+	e.t.Helper()
+	var errResp ErrorResponse
+	e.expect(method, path, code, &errResp)
+	if errResp.Error == "" {
+		e.t.Fatalf("%s %s 오류 응답에 error가 없음", method, path)
+	}
+	return errResp
+}
+
+func TestServerUnauthorized(t *testing.T) {
+	e := newTestEnv(t)
+
+	for _, auth := range []string{"", "Bearer wrong-token", "Bearer ", testToken, "Basic " + testToken} {
+		for _, path := range []string{"/api/tunnels", "/api/tunnels/web/stop", "/api/unknown"} {
+			resp, body := e.doWithAuth(http.MethodPost, path, auth)
+			if resp.StatusCode != http.StatusUnauthorized {
+				t.Fatalf("Authorization %q, %s 응답 코드 %d: %s", auth, path, resp.StatusCode, body)
+			}
+			if resp.Header.Get("WWW-Authenticate") != "Bearer" {
+				t.Fatalf("WWW-Authenticate 헤더가 없음: %q", resp.Header.Get("WWW-Authenticate"))
+			}
+			var errResp ErrorResponse
+			if err := json.Unmarshal(body, &errResp); err != nil || errResp.Error != "인증 토큰이 올바르지 않습니다" {
+				t.Fatalf("인증 오류 응답이 다름: %s", body)
+			}
+		}
+	}
+
+	// 인증 실패한 요청은 아무 작업도 하지 않아야 함
+	if status, _ := e.manager.GetTunnelStatus("web"); status.Status == "disconnected" {
+		t.Fatal("인증 실패한 stop 요청이 터널을 중지함")
+	}
+}
+
+func TestServerEmptyTokenRejectsAll(t *testing.T) {
+	e := newTestEnv(t)
+	e.server = httptest.NewServer(NewServer(e.manager, "").Handler())
+	defer e.server.Close()
+
+	if resp, body := e.doWithAuth(http.MethodGet, "/api/tunnels", "Bearer "); resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("빈 토큰 서버가 요청을 허용함: %d %s", resp.StatusCode, body)
+	}
+}
+
+func TestServerNotFound(t *testing.T) {
+	e := newTestEnv(t)
+
+	tests := []struct {
+		method string
+		path   string
+	}{
+		{http.MethodGet, "/api/tunnels/nope"},
+		{http.MethodPost, "/api/tunnels/nope/start"},
+		{http.MethodPost, "/api/tunnels/nope/stop"},
+		{http.MethodPost, "/api/tunnels/nope/restart"},
+		{http.MethodPost, "/api/tunnels/web/bogus"},
+		{http.MethodGet, "/api/tunnels/web/stop/extra"},
+		// start, stop도 터널 이름으로 처리 (전체 작업은 /api/actions 아래)
+		{http.MethodGet, "/api/tunnels/start"},
+		{http.MethodPost, "/api/tunnels/stop/start"},
+		{http.MethodPost, "/api/actions/bogus"},
+		{http.MethodGet, "/api/unknown"},
+		{http.MethodGet, "/"},
+	}
+	for _, tt := range tests {
+		errResp := e.expectError(tt.method, tt.path, http.StatusNotFound)
+		if errResp.Tunnel != nil {
+			t.Fatalf("%s %s 404 응답에 터널 상태가 포함됨", tt.method, tt.path)
+		}
+	}
+}
+
+func TestServerMethodNotAllowed(t *testing.T) {
+	e := newTestEnv(t)
+
+	tests := []struct {
+		method string
+		path   string
+		allow  string
+	}{
+		{http.MethodPost, "/api/tunnels", http.MethodGet},
+		{http.MethodDelete, "/api/tunnels", http.MethodGet},
+		{http.MethodPost, "/api/tunnels/web", http.MethodGet},
+		{http.MethodGet, "/api/tunnels/web/start", http.MethodPost},
+		{http.MethodGet, "/api/tunnels/web/stop", http.MethodPost},
+		{http.MethodGet, "/api/tunnels/web/restart", http.MethodPost},
+		{http.MethodGet, "/api/actions/start-all", http.MethodPost},
+		{http.MethodGet, "/api/actions/stop-all", http.MethodPost},
+		{http.MethodGet, "/api/reload", http.MethodPost},
+	}
+	for _, tt := range tests {
+		resp, body := e.do(tt.method, tt.path)
+		if resp.StatusCode != http.StatusMethodNotAllowed {
+			t.Fatalf("%s %s 응답 코드 %d: %s", tt.method, tt.path, resp.StatusCode, body)
+		}
+		if got := resp.Header.Get("Allow"); got != tt.allow {
+			t.Fatalf("%s %s Allow 헤더 %q (기대값 %q)", tt.method, tt.path, got, tt.allow)
+		}
+	}
+
+	// 잘못된 메서드의 요청은 작업을 수행하지 않아야 함
+	if status, _ := e.manager.GetTunnelStatus("web"); status.Status == "disconnected" {
+		t.Fatal("GET stop 요청이 터널을 중지함")
+	}
+}
+
+func TestServerTunnelActions(t *testing.T) {
+	e := newTestEnv(t)
+
+	var info TunnelInfo
+	e.expect(http.MethodPost, "/api/tunnels/web/start?wait=10", http.StatusOK, &info)
+	if info.Name != "web" || info.Status != "connected" {
+		t.Fatalf("start 후 상태가 다름: %+v", info)
+	}
+
+	e.expect(http.MethodPost, "/api/tunnels/web/stop", http.StatusOK, &info)
+	if info.Status != "disconnected" {
+		t.Fatalf("stop 후 상태가 다름: %+v", info)
+	}
+	// 중지한 터널은 모니터링에서 다시 시작하지 않음
+	e.expect(http.MethodGet, "/api/tunnels/web", http.StatusOK, &info)
+	if info.Status != "disconnected" {
+		t.Fatalf("stop 후 조회한 상태가 다름: %+v", info)
+	}
+
+	e.expect(http.MethodPost, "/api/tunnels/web/restart?wait=10", http.StatusOK, &info)
+	if info.Status != "connected" {
+		t.Fatalf("restart 후 상태가 다름: %+v", info)
+	}
+
+	// 유효하지 않은 wait 값은 작업 전에 거부
+	e.expectError(http.MethodPost, "/api/tunnels/web/stop?wait=abc", http.StatusBadRequest)
+	e.expectError(http.MethodPost, "/api/tunnels/web/start?wait=301", http.StatusBadRequest)
+	e.expect(http.MethodGet, "/api/tunnels/web", http.StatusOK, &info)
+	if info.Status != "connected" {
+		t.Fatalf("거부된 요청 후 상태가 바뀜: %+v", info)
+	}
+
+	// 전체 중지/시작은 모든 터널 상태 목록을 반환
+	var infos []TunnelInfo
+	e.expect(http.MethodPost, "/api/actions/stop-all", http.StatusOK, &infos)
+	if len(infos) != 2 {
+		t.Fatalf("전체 중지 응답의 터널 수가 다름: %+v", infos)
+	}
+	for _, info := range infos {
+		if info.Status != "disconnected" {
+			t.Fatalf("전체 중지 후 터널 '%s' 상태: %s", info.Name, info.Status)
+		}
+	}
+
+	e.expect(http.MethodPost, "/api/actions/start-all", http.StatusOK, &infos)
+	for _, info := range infos {
+		if info.Status == "disconnected" {
+			t.Fatalf("전체 시작 후 터널 '%s' 상태: %s", info.Name, info.Status)
+		}
+	}
+}
+
+func TestServerStartFailure(t *testing.T) {
+	e := newTestEnv(t)
+
+	// 연결할 수 없는 SSH 서버로 바꾼 뒤 다시 로드하면 wait 중 시간 초과 또는 실패 응답에 터널 상태 포함
+	e.sshPort = sshtest.FreePort(t)
+	e.writeConfig(e.tunnelYAML("web", true))
+	e.expect(http.MethodPost, "/api/reload", http.StatusOK, nil)
+
+	resp, body := e.do(http.MethodPost, "/api/tunnels/web/restart?wait=1")
+	if resp.StatusCode != http.StatusGatewayTimeout && resp.StatusCode != http.StatusConflict {
+		t.Fatalf("연결 실패 응답 코드 %d: %s", resp.StatusCode, body)
+	}
+	var errResp ErrorResponse
+	if err := json.Unmarshal(body, &errResp); err != nil || errResp.Error == "" || errResp.Tunnel == nil || errResp.Tunnel.Name != "web" {
+		t.Fatalf("연결 실패 응답이 다름: %s", body)
+	}
+}
+
+func TestServerReload(t *testing.T) {
+	e := newTestEnv(t)
+
+	// 같은 설정을 다시 로드하면 모두 유지
+	var result ReloadResult
+	e.expect(http.MethodPost, "/api/reload", http.StatusOK, &result)
+	want := ReloadResult{Added: []string{}, Removed: []string{}, Changed: []string{}, Unchanged: []string{"web", "db"}, Skipped: []string{}}
+	if !reflect.DeepEqual(result, want) {
+		t.Fatalf("다시 로드 결과가 다름\n got: %+v\nwant: %+v", result, want)
+	}
+
+	// db 비활성화, cache 추가, web 포트 변경
+	e.writeConfig(e.tunnelYAML("web", true), e.tunnelYAML("db", false), e.tunnelYAML("cache", true))
+	e.expect(http.MethodPost, "/api/reload", http.StatusOK, &result)
+	want = ReloadResult{Added: []string{"cache"}, Removed: []string{"db"}, Changed: []string{"web"}, Unchanged: []string{}, Skipped: []string{}}
+	if !reflect.DeepEqual(result, want) {
+		t.Fatalf("다시 로드 결과가 다름\n got: %+v\nwant: %+v", result, want)
+	}
+
+	var infos []TunnelInfo
+	e.expect(http.MethodGet, "/api/tunnels", http.StatusOK, &infos)
+	var names []string
+	for _, info := range infos {
+		names = append(names, info.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"web", "cache"}) {
+		t.Fatalf("다시 로드 후 터널 목록이 다름: %v", names)
+	}
+	e.expectError(http.MethodGet, "/api/tunnels/db", http.StatusNotFound)
+
+	// 파싱할 수 없는 설정은 422로 거부하고 기존 터널 유지
+	if err := os.WriteFile(e.configPath, []byte("tunnels: [\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	e.expectError(http.MethodPost, "/api/reload", http.StatusUnprocessableEntity)
+	e.expect(http.MethodGet, "/api/tunnels/cache", http.StatusOK, nil)
+}
+
+func TestServerStatusJSON(t *testing.T) {
+	e := newTestEnv(t)
+	e.expect(http.MethodPost, "/api/tunnels/web/start?wait=10", http.StatusOK, nil)
+
+	// 필드 이름과 생략 규칙은 CLI와 외부 스크립트가 의존하므로 JSON 그대로 확인
+	var raw map[string]json.RawMessage
+	e.expect(http.MethodGet, "/api/tunnels/web", http.StatusOK, &raw)
+	assertKeys(t, raw, "name", "status", "connection", "forwards", "last_check")
+
+	var info TunnelInfo
+	e.expect(http.MethodGet, "/api/tunnels/web", http.StatusOK, &info)
+	if info.Name != "web" || info.Status != "connected" || info.LastCheck == nil {
+		t.Fatalf("터널 상태가 다름: %+v", info)
+	}
+	if !strings.Contains(info.Connection, fmt.Sprintf("127.0.0.1:%d", e.sshPort)) {
+		t.Fatalf("connection에 SSH 서버 주소가 없음: %q", info.Connection)
+	}
+
+	var forwards []map[string]json.RawMessage
+	if err := json.Unmarshal(raw["forwards"], &forwards); err != nil || len(forwards) != 1 {
+		t.Fatalf("forwards 형식이 다름: %s", raw["forwards"])
+	}
+	assertKeys(t, forwards[0], "label", "type", "local_port", "remote_port", "status", "latency_ms")
+	if f := info.Forwards[0]; f.Type != "local" || f.RemotePort != 80 || f.Status != "connected" || f.LocalPort == 0 {
+		t.Fatalf("포워딩 상태가 다름: %+v", f)
+	}
+
+	// 목록 응답은 같은 형식의 배열
+	var list []map[string]json.RawMessage
+	e.expect(http.MethodGet, "/api/tunnels", http.StatusOK, &list)
+	if len(list) != 2 {
+		t.Fatalf("터널 목록 수가 다름: %d", len(list))
+	}
+	for _, item := range list {
+		for _, key := range []string{"name", "status", "connection", "forwards"} {
+			if _, ok := item[key]; !ok {
+				t.Fatalf("목록 항목에 %s가 없음: %v", key, item)
+			}
+		}
+	}
+
+	// 중지된 터널도 forwards는 null이 아닌 배열
+	e.expect(http.MethodPost, "/api/tunnels/db/stop", http.StatusOK, &raw)
+	if string(raw["status"]) != `"disconnected"` || !strings.HasPrefix(string(raw["forwards"]), "[") {
+		t.Fatalf("중지된 터널 응답이 다름: status=%s forwards=%s", raw["status"], raw["forwards"])
+	}
+}
+
+// assertKeys JSON 객체의 키가 정확히 keys인지 확인 (값이 없는 선택 필드는 생략되어야 함)
+func assertKeys(t *testing.T, obj map[string]json.RawMessage, keys ...string) {
+	t.Helper()
+	var got []string
+	for key := range obj {
+		got = append(got, key)
+	}
+	sort.Strings(got)
+	sort.Strings(keys)
+	if !reflect.DeepEqual(got, keys) {
+		t.Fatalf("JSON 키가 다름\n got: %v\nwant: %v", got, keys)
+	}
+}
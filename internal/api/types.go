@@ -0,0 +1,107 @@
+package api
+
+import (
+	"time"
+
+	"tunnels/internal/manager"
+)
+
+// TunnelInfo 터널 상태 JSON 응답
+type TunnelInfo struct {
+	Name       string        `json:"name"`
+	Status     string        `json:"status"`            // disconnected, connecting, connected, degraded 또는 error
+	Failure    string        `json:"failure,omitempty"` // 실패 원인 분류 (auth, unreachable, port_in_use 등)
+	LastError  string        `json:"last_error,omitempty"`
+	Connection string        `json:"connection"`
+	Forwards   []ForwardInfo `json:"forwards"`
+	LastCheck  *time.Time    `json:"last_check,omitempty"`
+	NextRetry  *time.Time    `json:"next_retry,omitempty"` // 다음 자동 재시작 예정 시간
+	ExitCode   *int          `json:"exit_code,omitempty"`  // 마지막 ssh 프로세스 종료 코드
+	ExitTime   *time.Time    `json:"exit_time,omitempty"`
+	Output     []string      `json:"output,omitempty"` // ssh 프로세스 stderr 최근 출력
+}
+
+// ForwardInfo 포워딩 상태 JSON 응답
+type ForwardInfo struct {
+	Label      string  `json:"label"`
+	Type       string  `json:"type"` // local, remote 또는 dynamic
+	LocalPort  int     `json:"local_port"`
+	RemotePort int     `json:"remote_port,omitempty"` // remote는 SSH 서버에서 리슨하는 포트
+	Status     string  `json:"status"`
+	LastError  string  `json:"last_error,omitempty"`
+	LatencyMS  float64 `json:"latency_ms,omitempty"` // 마지막 상태 확인(프로브)에 걸린 시간
+}
+
+// ReloadResult 설정 다시 로드 결과 JSON 응답
+type ReloadResult struct {
+	Added     []string `json:"added"`
+	Removed   []string `json:"removed"`
+	Changed   []string `json:"changed"`
+	Unchanged []string `json:"unchanged"`
+	Skipped   []string `json:"skipped"`
+}
+
+// ErrorResponse 오류 JSON 응답
+type ErrorResponse struct {
+	Error  string      `json:"error"`
+	Tunnel *TunnelInfo `json:"tunnel,omitempty"` // 터널 작업 중 실패한 경우 현재 상태
+}
+
+// NewTunnelInfo 매니저 터널 상태를 JSON 응답 형식으로 변환
+func NewTunnelInfo(status manager.TunnelStatus) TunnelInfo {
+	info := TunnelInfo{
+		Name:       status.Name,
+		Status:     string(status.Status),
+		Failure:    string(status.Failure),
+		LastError:  status.LastError,
+		Connection: status.Connection,
+		Forwards:   make([]ForwardInfo, len(status.Forwards)),
+		LastCheck:  timePtr(status.LastCheck),
+		NextRetry:  timePtr(status.NextRetry),
+		ExitTime:   timePtr(status.ExitTime),
+		Output:     status.Output,
+	}
+	if status.ExitCode >= 0 {
+		exitCode := status.ExitCode
+		info.ExitCode = &exitCode
+	}
+	for i, f := range status.Forwards {
+		info.Forwards[i] = ForwardInfo{
+			Label:      f.Label,
+			Type:       f.Config.GetType(),
+			LocalPort:  f.Config.LocalPort,
+			RemotePort: f.Config.RemotePort,
+			Status:     string(f.Status),
+			LastError:  f.LastError,
+			LatencyMS:  float64(f.Latency.Microseconds()) / 1000,
+		}
+	}
+	return info
+}
+
+// newReloadResult 매니저 다시 로드 결과를 JSON 응답 형식으로 변환 (빈 목록은 null 대신 [])
+func newReloadResult(summary manager.ReloadSummary) ReloadResult {
+	return ReloadResult{
+		Added:     nonNil(summary.Added),
+		Removed:   nonNil(summary.Removed),
+		Changed:   nonNil(summary.Changed),
+		Unchanged: nonNil(summary.Unchanged),
+		Skipped:   nonNil(summary.Skipped),
+	}
+}
+
+// timePtr zero 시간은 JSON에서 생략되도록 nil로 변환
+func timePtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// nonNil nil 슬라이스를 빈 슬라이스로 변환
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
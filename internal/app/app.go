@@ -6,11 +6,14 @@ import (
 	"log"
 	"os/exec"
 	"runtime"
+	"strconv"
 	"strings"
-	"syscall"
+	"sync"
 	"time"
 
+	"tunnels/internal/config"
 	"tunnels/internal/manager"
+	"tunnels/internal/service"
 	"tunnels/internal/tunnel"
 	"tunnels/internal/version"
 
@@ -19,9 +22,16 @@ import (
 
 // TunnelApp 메인 애플리케이션
 type TunnelApp struct {
-	manager     *manager.Manager
-	configPath  string
+	service    *service.Service // 헤드리스 모드와 공유하는 실행 수명 주기
+	manager    *manager.Manager
+	configPath string
+	// menuMu 터널 메뉴 아이템 맵 보호 (상태 갱신 루프, 설정 파일 감시, 메뉴 동작 goroutine에서 함께 사용)
+	menuMu      sync.Mutex
 	statusItems map[string]*systray.MenuItem
+	// 포워딩이 여러 개인 터널의 포워딩별 하위 메뉴 아이템
+	forwardItems map[string][]*systray.MenuItem
+	// 터널별 동작 하위 메뉴 아이템 (Connect, Disconnect 등)
+	actionItems map[string]*tunnelActions
 	quitCh      chan bool
 	iconPath    string
 	iconAssets  embed.FS // 아이콘 에셋
@@ -30,11 +40,13 @@ type TunnelApp struct {
 // NewTunnelApp 새 앱 인스턴스 생성
 func NewTunnelApp(configPath string, iconAssets embed.FS) *TunnelApp {
 	return &TunnelApp{
-		configPath:  configPath,
-		statusItems: make(map[string]*systray.MenuItem),
-		quitCh:      make(chan bool),
-		iconPath:    "disconnected",
-		iconAssets:  iconAssets,
+		configPath:   configPath,
+		statusItems:  make(map[string]*systray.MenuItem),
+		forwardItems: make(map[string][]*systray.MenuItem),
+		actionItems:  make(map[string]*tunnelActions),
+		quitCh:       make(chan bool),
+		iconPath:     "disconnected",
+		iconAssets:   iconAssets,
 	}
 }
 
@@ -64,15 +76,15 @@ func (app *TunnelApp) OnReady() {
 	// 트레이 상태 로그 출력
 	app.logTrayStatus()
 
-	// 시작 시 기존 SSH 프로세스 정리
-	app.CleanupSSHProcesses()
-
 	// 매니저 초기화
-	app.manager = manager.NewManager(app.configPath)
+	app.service = service.New(app.configPath)
+	app.manager = app.service.Manager()
+
+	// 설정 파일 변경이나 제어 API로 다시 로드되면 메뉴와 아이콘 갱신
+	app.manager.SetReloadHandler(app.onConfigReloaded)
 
-	// 설정 로드 및 터널 시작
-	if err := app.manager.LoadConfig(); err != nil {
-		log.Printf("초기 설정 로드 실패: %v", err)
+	// 기존 SSH 프로세스 정리, 설정 로드 및 터널 시작, 모니터링과 제어 API 시작
+	if err := app.service.Start(); err != nil {
 		systray.SetTooltip("Tunnels - 설정 로드 실패")
 	} else {
 		// 터널 시작 후 아이콘 업데이트
@@ -80,9 +92,6 @@ func (app *TunnelApp) OnReady() {
 		app.updateTrayIcon()
 	}
 
-	// 모니터링 시작
-	app.manager.StartMonitoring()
-
 	// 메뉴 구성
 	app.setupMenu()
 
@@ -109,24 +118,8 @@ func (app *TunnelApp) OnExit() {
 
 	log.Println("애플리케이션 종료 중...")
 
-	if app.manager != nil {
-		log.Println("모든 터널 중지 중...")
-
-		// 안전하게 터널 중지
-		func() {
-			defer func() {
-				if r := recover(); r != nil {
-					log.Printf("터널 중지 중 panic 발생: %v", r)
-				}
-			}()
-			app.manager.StopAll()
-		}()
-
-		// SSH 프로세스 정리 대기
-		time.Sleep(3 * time.Second)
-
-		// 남은 SSH 프로세스 강제 종료
-		app.CleanupSSHProcesses()
+	if app.service != nil {
+		app.service.Stop()
 	}
 
 	// quitCh 안전하게 닫기
@@ -155,8 +148,8 @@ func (app *TunnelApp) setupMenu() {
 	// 구분선
 	systray.AddSeparator()
 
-	// 설정 다시 로드 및 터널 재시작 (통합)
-	reloadAndRestartItem := systray.AddMenuItem("Reload Config", "Reload config file and restart all tunnels")
+	// 설정 다시 로드 (바뀐 터널만 재시작)
+	reloadAndRestartItem := systray.AddMenuItem("Reload Config", "Reload config file and restart changed tunnels")
 	go func() {
 		for range reloadAndRestartItem.ClickedCh {
 			app.reloadConfigAndRestart()
@@ -183,12 +176,13 @@ func (app *TunnelApp) setupMenu() {
 	}()
 }
 
-// reloadConfigAndRestart 설정 다시 로드 및 모든 터널 재시작
+// reloadConfigAndRestart 설정 다시 로드 및 바뀐 터널 재시작
 func (app *TunnelApp) reloadConfigAndRestart() {
-	log.Println("설정 다시 로드 및 터널 재시작 중...")
+	log.Println("설정 다시 로드 중...")
 
-	// 설정 다시 로드 (이미 터널 재시작도 포함됨)
-	if err := app.manager.LoadConfig(); err != nil {
+	// 설정 다시 로드 (추가/변경된 터널 시작, 삭제된 터널 중지, 나머지는 유지)
+	summary, err := app.manager.LoadConfig()
+	if err != nil {
 		log.Printf("설정 로드 실패: %v", err)
 		app.showError("설정 로드 실패", err.Error())
 		return
@@ -200,7 +194,16 @@ func (app *TunnelApp) reloadConfigAndRestart() {
 	// 아이콘 업데이트
 	app.updateTrayIcon()
 
-	log.Println("설정 다시 로드 및 터널 재시작 완료")
+	log.Printf("설정 다시 로드 완료: %s", summary)
+}
+
+// onConfigReloaded 설정 파일 변경이나 제어 API로 다시 로드된 뒤 호출 (실패하면 이전 설정 유지, 툴팁에 오류 표시)
+func (app *TunnelApp) onConfigReloaded(summary manager.ReloadSummary, err error) {
+	if err == nil {
+		app.updateMenuForConfigReload()
+		app.updateTrayIcon()
+	}
+	app.updateStatus()
 }
 
 // openConfigFile 설정 파일 열기
@@ -266,6 +269,10 @@ func (app *TunnelApp) updateStatus() {
 
 	// 툴팁 업데이트 (안전하게 처리)
 	tooltip := fmt.Sprintf("%s - %d/%d connected", version.AppName, healthyCount, totalCount)
+	// 설정 파일을 읽지 못해 이전 설정으로 동작 중이면 오류 표시
+	if configError := app.manager.GetConfigError(); configError != "" {
+		tooltip += "\nConfig error: " + configError
+	}
 	func() {
 		defer func() {
 			if r := recover(); r != nil {
@@ -285,29 +292,112 @@ func (app *TunnelApp) createStatusItems() {
 		return
 	}
 
-	tunnelStatuses := app.manager.GetTunnelStatuses()
+	app.menuMu.Lock()
+	defer app.menuMu.Unlock()
+
+	// 비활성화된 터널도 표시 (하위 메뉴에서 활성화 가능)
+	tunnelStatuses := app.manager.GetAllTunnelStatuses()
 	for _, tunnelStatus := range tunnelStatuses {
-		statusText := app.formatTunnelStatus(tunnelStatus)
-		item := systray.AddMenuItem(statusText, fmt.Sprintf("Tunnel: %s", tunnelStatus.Name))
-		// 상태에 따른 아이콘 설정
-		app.setStatusIcon(item, tunnelStatus.Status)
-		app.statusItems[tunnelStatus.Name] = item
+		app.addStatusItem(tunnelStatus)
 	}
 }
 
+// addStatusItem 터널 하나의 상태 메뉴 아이템 생성 (동작 하위 메뉴, 포워딩이 여러 개면 포워딩별 상태 포함, menuMu를 잡은 상태에서 호출)
+func (app *TunnelApp) addStatusItem(tunnelStatus manager.TunnelStatus) {
+	statusText := app.formatTunnelStatus(tunnelStatus)
+	item := systray.AddMenuItem(statusText, formatTunnelTooltip(tunnelStatus))
+	// 상태에 따른 아이콘 설정
+	app.setStatusIcon(item, tunnelStatus.Status)
+	app.statusItems[tunnelStatus.Name] = item
+
+	actions := app.addTunnelActions(item, tunnelStatus.Name)
+	app.actionItems[tunnelStatus.Name] = actions
+	updateTunnelActions(actions, tunnelStatus)
+
+	app.updateForwardItems(item, tunnelStatus)
+}
+
+// formatTunnelTooltip 터널 메뉴 아이템 툴팁 (오류 상태면 오류 메시지, 연결 상태면 상태 확인 지연 시간 포함)
+func formatTunnelTooltip(status manager.TunnelStatus) string {
+	if status.Disabled {
+		return fmt.Sprintf("Tunnel: %s\nDisabled in config", status.Name)
+	}
+	if (status.Status == tunnel.StatusError || status.Status == tunnel.StatusDegraded) && status.LastError != "" {
+		return fmt.Sprintf("Tunnel: %s\n%s", status.Name, status.LastError)
+	}
+	tooltip := fmt.Sprintf("Tunnel: %s", status.Name)
+	if status.Status == tunnel.StatusConnected {
+		for _, f := range status.Forwards {
+			if f.Status == tunnel.StatusConnected {
+				tooltip += fmt.Sprintf("\n%s: %s", f.Label, formatLatency(f))
+			}
+		}
+	}
+	return tooltip
+}
+
+// formatLatency 포워딩 상태 확인 지연 시간 (프로브가 있으면 프로브 종류 포함)
+func formatLatency(f tunnel.ForwardStatus) string {
+	latency := f.Latency.Round(time.Millisecond).String()
+	if f.Config.Probe != nil {
+		return fmt.Sprintf("%s (%s probe)", latency, f.Config.Probe.Type)
+	}
+	return latency
+}
+
+// updateForwardItems 포워딩별 하위 메뉴 아이템 생성/업데이트 (menuMu를 잡은 상태에서 호출)
+func (app *TunnelApp) updateForwardItems(parent *systray.MenuItem, tunnelStatus manager.TunnelStatus) {
+	// 포워딩이 하나뿐이면 터널 아이템으로 충분
+	if len(tunnelStatus.Forwards) <= 1 && len(app.forwardItems[tunnelStatus.Name]) == 0 {
+		return
+	}
+
+	items := app.forwardItems[tunnelStatus.Name]
+	for i, forwardStatus := range tunnelStatus.Forwards {
+		text := app.formatForwardStatus(forwardStatus)
+		tooltip := forwardStatus.LastError
+		if forwardStatus.Status == tunnel.StatusConnected {
+			tooltip = formatLatency(forwardStatus)
+		}
+		if i < len(items) {
+			items[i].SetTitle(text)
+			items[i].SetTooltip(tooltip)
+			items[i].Show()
+			continue
+		}
+		sub := parent.AddSubMenuItem(text, tooltip)
+		sub.Disable() // 상태 표시 전용
+		items = append(items, sub)
+	}
+
+	// 설정 변경으로 줄어든 포워딩은 숨김
+	for i := len(tunnelStatus.Forwards); i < len(items); i++ {
+		items[i].Hide()
+	}
+	app.forwardItems[tunnelStatus.Name] = items
+}
+
 // updateStatusItems 터널 상태 메뉴 아이템들 업데이트
 func (app *TunnelApp) updateStatusItems() {
 	if app.manager == nil {
 		return
 	}
 
-	tunnelStatuses := app.manager.GetTunnelStatuses()
+	tunnelStatuses := app.manager.GetAllTunnelStatuses()
+
+	app.menuMu.Lock()
+	defer app.menuMu.Unlock()
 	for _, tunnelStatus := range tunnelStatuses {
 		if item, exists := app.statusItems[tunnelStatus.Name]; exists {
 			statusText := app.formatTunnelStatus(tunnelStatus)
 			item.SetTitle(statusText)
+			item.SetTooltip(formatTunnelTooltip(tunnelStatus))
 			// 상태에 따른 아이콘 업데이트
 			app.setStatusIcon(item, tunnelStatus.Status)
+			if actions, ok := app.actionItems[tunnelStatus.Name]; ok {
+				updateTunnelActions(actions, tunnelStatus)
+			}
+			app.updateForwardItems(item, tunnelStatus)
 		}
 	}
 }
@@ -318,17 +408,23 @@ func (app *TunnelApp) updateMenuForConfigReload() {
 		return
 	}
 
-	// 현재 설정의 터널 목록 가져오기
+	// 현재 설정의 터널 목록 가져오기 (비활성화된 터널 포함)
 	currentTunnels := make(map[string]bool)
-	for _, tunnelConfig := range app.manager.GetConfig().GetEnabledTunnels() {
+	for _, tunnelConfig := range app.manager.GetConfig().Tunnels {
 		currentTunnels[tunnelConfig.Name] = true
 	}
 
+	app.menuMu.Lock()
 	// 기존 상태 항목들 중 제거된 터널들 숨기기
 	for name, item := range app.statusItems {
 		if !currentTunnels[name] {
 			item.Hide()
+			if actions, ok := app.actionItems[name]; ok {
+				close(actions.done)
+			}
 			delete(app.statusItems, name)
+			delete(app.forwardItems, name)
+			delete(app.actionItems, name)
 		}
 	}
 
@@ -336,18 +432,16 @@ func (app *TunnelApp) updateMenuForConfigReload() {
 	for name := range currentTunnels {
 		if _, exists := app.statusItems[name]; !exists {
 			// 새 터널에 대한 상태 항목 생성
-			tunnelStatuses := app.manager.GetTunnelStatuses()
+			tunnelStatuses := app.manager.GetAllTunnelStatuses()
 			for _, tunnelStatus := range tunnelStatuses {
 				if tunnelStatus.Name == name {
-					statusText := app.formatTunnelStatus(tunnelStatus)
-					item := systray.AddMenuItem(statusText, fmt.Sprintf("Tunnel: %s", name))
-					app.setStatusIcon(item, tunnelStatus.Status)
-					app.statusItems[name] = item
+					app.addStatusItem(tunnelStatus)
 					break
 				}
 			}
 		}
 	}
+	app.menuMu.Unlock()
 
 	// 기존 상태 항목들 업데이트
 	app.updateStatusItems()
@@ -355,31 +449,91 @@ func (app *TunnelApp) updateMenuForConfigReload() {
 
 // formatTunnelStatus 터널 상태 포맷팅
 func (app *TunnelApp) formatTunnelStatus(status manager.TunnelStatus) string {
-	config := status.Config
+	port := formatPort(status.Config)
 	var statusText string
 
+	// 세션은 연결되어 있지만 일부 포워딩이 실패한 경우
+	healthy := 0
+	for _, f := range status.Forwards {
+		if f.Status == tunnel.StatusConnected {
+			healthy++
+		}
+	}
+
+	switch {
+	case status.Disabled:
+		statusText = fmt.Sprintf("○ %s (%s) [DISABLED]",
+			status.Name, port)
+	case status.Status == tunnel.StatusConnected:
+		if healthy < len(status.Forwards) {
+			statusText = fmt.Sprintf("◐ %s (%s) [PARTIAL %d/%d]",
+				status.Name, port, healthy, len(status.Forwards))
+		} else {
+			statusText = fmt.Sprintf("● %s (%s) [CONNECTED]",
+				status.Name, port)
+		}
+	case status.Status == tunnel.StatusDegraded:
+		// SSH 세션은 유지되므로 재시작 예정 시간 없이 실패 원인만 표시
+		statusText = fmt.Sprintf("◐ %s (%s) [%s]",
+			status.Name, port, status.Failure.Label())
+	case status.Status == tunnel.StatusConnecting:
+		statusText = fmt.Sprintf("⊙ %s (%s) [CONNECTING...]",
+			status.Name, port)
+	case status.Status == tunnel.StatusError:
+		// 실패 원인별 표시 (인증 실패, 연결 불가, 포트 사용 중 등)
+		statusText = fmt.Sprintf("⊗ %s (%s) [%s]",
+			status.Name, port, status.Failure.Label())
+		// 자동 재시작이 예약되어 있으면 예정 시간 표시
+		if !status.NextRetry.IsZero() {
+			statusText += fmt.Sprintf(" - retry %s", status.NextRetry.Format("15:04:05"))
+		}
+	default:
+		statusText = fmt.Sprintf("○ %s (%s) [DISCONNECTED]",
+			status.Name, port)
+	}
+
+	return statusText
+}
+
+// formatForwardStatus 포워딩 상태 포맷팅 (하위 메뉴용)
+func (app *TunnelApp) formatForwardStatus(status tunnel.ForwardStatus) string {
+	label := status.Label
+	if status.Config.Name != "" {
+		label = fmt.Sprintf("%s (%s)", label, formatForwardPort(status.Config))
+	}
+
 	switch status.Status {
 	case tunnel.StatusConnected:
-		statusText = fmt.Sprintf("● %s (%d) [CONNECTED]",
-			status.Name, config.LocalPort)
+		return fmt.Sprintf("● %s [CONNECTED]", label)
 	case tunnel.StatusConnecting:
-		statusText = fmt.Sprintf("⊙ %s (%d) [CONNECTING...]",
-			status.Name, config.LocalPort)
+		return fmt.Sprintf("⊙ %s [CONNECTING...]", label)
 	case tunnel.StatusError:
-		// 키 파일 권한 오류인지 확인
-		if strings.Contains(status.LastError, "키 파일 권한 오류") {
-			statusText = fmt.Sprintf("⊗ %s (%d) [AUTH ERROR]",
-				status.Name, config.LocalPort)
-		} else {
-			statusText = fmt.Sprintf("⊗ %s (%d) [ERROR]",
-				status.Name, config.LocalPort)
-		}
+		return fmt.Sprintf("⊗ %s [ERROR]", label)
 	default:
-		statusText = fmt.Sprintf("○ %s (%d) [DISCONNECTED]",
-			status.Name, config.LocalPort)
+		return fmt.Sprintf("○ %s [DISCONNECTED]", label)
 	}
+}
 
-	return statusText
+// formatPort 메뉴에 표시할 포트 목록 (쉼표로 구분)
+func formatPort(cfg config.TunnelConfig) string {
+	forwards := cfg.GetForwards()
+	ports := make([]string, len(forwards))
+	for i, f := range forwards {
+		ports[i] = formatForwardPort(f)
+	}
+	return strings.Join(ports, ",")
+}
+
+// formatForwardPort 포워딩 하나의 포트 (원격 포워딩은 SSH 서버 쪽 포트에 R:, SOCKS5 프록시는 D: 표시)
+func formatForwardPort(f config.ForwardConfig) string {
+	switch f.GetType() {
+	case config.TypeRemote:
+		return fmt.Sprintf("R:%d", f.RemotePort)
+	case config.TypeDynamic:
+		return fmt.Sprintf("D:%d", f.LocalPort)
+	default:
+		return strconv.Itoa(f.LocalPort)
+	}
 }
 
 // setStatusIcon 메뉴 아이템에 상태에 따른 아이콘 설정 (텍스트 기반)
@@ -392,9 +546,11 @@ func (app *TunnelApp) setStatusIcon(item *systray.MenuItem, status tunnel.Status
 // showError 에러 메시지 표시 (Windows에서는 메시지 박스 사용)
 func (app *TunnelApp) showError(title, message string) {
 	if runtime.GOOS == "windows" {
+		// PowerShell 작은따옴표 문자열 안의 작은따옴표는 두 번 써서 이스케이프
+		quote := func(s string) string { return strings.ReplaceAll(s, "'", "''") }
 		cmd := exec.Command("powershell", "-Command",
 			fmt.Sprintf("Add-Type -AssemblyName System.Windows.Forms; [System.Windows.Forms.MessageBox]::Show('%s', '%s')",
-				message, title))
+				quote(message), quote(title)))
 		cmd.Run()
 	} else {
 		log.Printf("ERROR [%s]: %s", title, message)
@@ -483,32 +639,3 @@ func (app *TunnelApp) updateTrayIcon() {
 func (app *TunnelApp) logTrayStatus() {
 	log.Printf("=== %s 애플리케이션 시작됨 ===", version.AppFullName)
 }
-
-// CleanupSSHProcesses 남은 SSH 프로세스 정리 (Public)
-func (app *TunnelApp) CleanupSSHProcesses() {
-	// 여러 방법으로 SSH 프로세스 정리 시도
-	cleanupMethods := []struct {
-		name string
-		cmd  *exec.Cmd
-	}{
-		{"taskkill /f /im ssh.exe", exec.Command("taskkill", "/f", "/im", "ssh.exe")},
-		{"taskkill /f /im OpenSSH", exec.Command("taskkill", "/f", "/im", "OpenSSH")},
-		{"wmic process where name='ssh.exe' delete", exec.Command("wmic", "process", "where", "name='ssh.exe'", "delete")},
-	}
-
-	for _, method := range cleanupMethods {
-		// 조용히 실행 (출력 숨김)
-		method.cmd.SysProcAttr = &syscall.SysProcAttr{
-			HideWindow:    true,
-			CreationFlags: 0x08000000, // CREATE_NO_WINDOW
-		}
-
-		if err := method.cmd.Run(); err != nil {
-			log.Printf("SSH 프로세스 정리 방법 '%s' 실패: %v", method.name, err)
-		} else {
-			log.Printf("SSH 프로세스 정리 방법 '%s' 성공", method.name)
-		}
-	}
-
-	log.Println("SSH 프로세스 정리 완료")
-}
@@ -0,0 +1,18 @@
+package app
+
+import (
+	"os/exec"
+	"strings"
+	"syscall"
+)
+
+// copyToClipboard 텍스트를 클립보드에 복사 (clip.exe 사용, 콘솔 창 숨김)
+func copyToClipboard(text string) error {
+	cmd := exec.Command("clip")
+	cmd.Stdin = strings.NewReader(text)
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		HideWindow:    true,
+		CreationFlags: 0x08000000, // CREATE_NO_WINDOW
+	}
+	return cmd.Run()
+}
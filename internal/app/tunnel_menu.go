@@ -0,0 +1,185 @@
+package app
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"tunnels/internal/config"
+	"tunnels/internal/manager"
+	"tunnels/internal/tunnel"
+
+	"github.com/getlantern/systray"
+)
+
+// tunnelActions 터널 하위 메뉴의 동작 아이템들
+type tunnelActions struct {
+	connect    *systray.MenuItem
+	disconnect *systray.MenuItem
+	restart    *systray.MenuItem
+	resetRetry *systray.MenuItem
+	copyAddr   *systray.MenuItem
+	viewError  *systray.MenuItem
+	toggle     *systray.MenuItem // Enable/Disable (설정 파일에 저장)
+	done       chan struct{}     // 터널이 설정에서 삭제되어 메뉴 아이템을 숨기면 닫힘 (클릭 처리 goroutine 종료)
+}
+
+// addTunnelActions 터널 메뉴 아이템 아래에 동작 하위 메뉴 생성 (포워딩 상태 아이템보다 먼저 추가)
+func (app *TunnelApp) addTunnelActions(parent *systray.MenuItem, name string) *tunnelActions {
+	actions := &tunnelActions{
+		connect:    parent.AddSubMenuItem("Connect", "Start this tunnel"),
+		disconnect: parent.AddSubMenuItem("Disconnect", "Stop this tunnel"),
+		restart:    parent.AddSubMenuItem("Restart", "Restart this tunnel"),
+		resetRetry: parent.AddSubMenuItem("Reset retries", "Reset the retry count and reconnect now"),
+		copyAddr:   parent.AddSubMenuItem("Copy local address", "Copy the local address to the clipboard"),
+		viewError:  parent.AddSubMenuItem("View last error", "Show the last error and recent ssh output"),
+		toggle:     parent.AddSubMenuItem("Disable", "Disable this tunnel and save the config file"),
+		done:       make(chan struct{}),
+	}
+
+	go func() {
+		for {
+			select {
+			case <-app.quitCh:
+				return
+			case <-actions.done:
+				return
+			case <-actions.connect.ClickedCh:
+				app.runTunnelAction(name, "시작", app.manager.StartTunnel)
+			case <-actions.disconnect.ClickedCh:
+				app.runTunnelAction(name, "중지", app.manager.StopTunnel)
+			case <-actions.restart.ClickedCh:
+				app.runTunnelAction(name, "재시작", app.manager.RestartTunnel)
+			case <-actions.resetRetry.ClickedCh:
+				app.runTunnelAction(name, "재시도 초기화", app.manager.ResetRetries)
+			case <-actions.copyAddr.ClickedCh:
+				app.copyLocalAddress(name)
+			case <-actions.viewError.ClickedCh:
+				app.viewLastError(name)
+			case <-actions.toggle.ClickedCh:
+				app.toggleTunnel(name)
+			}
+		}
+	}()
+	return actions
+}
+
+// updateTunnelActions 터널 상태에 따라 동작 아이템 활성화/비활성화
+func updateTunnelActions(actions *tunnelActions, status manager.TunnelStatus) {
+	// 비활성화된 터널은 Enable만 가능
+	if status.Disabled {
+		for _, item := range []*systray.MenuItem{actions.connect, actions.disconnect, actions.restart,
+			actions.resetRetry, actions.copyAddr, actions.viewError} {
+			item.Disable()
+		}
+		actions.toggle.SetTitle("Enable")
+		actions.toggle.SetTooltip("Enable this tunnel and save the config file")
+		return
+	}
+	actions.toggle.SetTitle("Disable")
+	actions.toggle.SetTooltip("Disable this tunnel and save the config file")
+	actions.restart.Enable()
+
+	setEnabled(actions.connect, status.Status == tunnel.StatusDisconnected || status.Status == tunnel.StatusError)
+	setEnabled(actions.disconnect, status.Status != tunnel.StatusDisconnected)
+	setEnabled(actions.resetRetry, status.Status == tunnel.StatusError)
+	setEnabled(actions.viewError, status.LastError != "")
+
+	addresses := localAddresses(status.Config)
+	setEnabled(actions.copyAddr, len(addresses) > 0)
+	if len(addresses) > 0 {
+		actions.copyAddr.SetTooltip(strings.Join(addresses, "\n"))
+	}
+}
+
+// setEnabled 메뉴 아이템 활성화 상태 설정
+func setEnabled(item *systray.MenuItem, enabled bool) {
+	if enabled {
+		item.Enable()
+	} else {
+		item.Disable()
+	}
+}
+
+// runTunnelAction 터널 하나에 대한 작업을 실행하고 메뉴 갱신 (실패하면 오류 표시)
+func (app *TunnelApp) runTunnelAction(name, label string, action func(string) error) {
+	go func() {
+		log.Printf("터널 '%s' %s 요청 (트레이 메뉴)", name, label)
+		if err := action(name); err != nil {
+			log.Printf("터널 '%s' %s 실패: %v", name, label, err)
+			app.showError(fmt.Sprintf("%s %s 실패", name, label), err.Error())
+		}
+		app.updateStatus()
+		app.updateTrayIcon()
+	}()
+}
+
+// toggleTunnel 터널 활성화/비활성화 전환 (설정 파일의 enabled 값만 고쳐서 저장한 뒤 바로 적용)
+func (app *TunnelApp) toggleTunnel(name string) {
+	enabled := false
+	if cfg := app.manager.GetConfig(); cfg != nil {
+		for _, tunnelConfig := range cfg.Tunnels {
+			if tunnelConfig.Name == name {
+				enabled = tunnelConfig.Enabled
+				break
+			}
+		}
+	}
+
+	label := "활성화"
+	if enabled {
+		label = "비활성화"
+	}
+	app.runTunnelAction(name, label, func(name string) error {
+		return app.manager.SetTunnelEnabled(name, !enabled)
+	})
+}
+
+// copyLocalAddress 터널의 로컬 주소를 클립보드에 복사 (포워딩이 여러 개면 줄마다 하나씩)
+func (app *TunnelApp) copyLocalAddress(name string) {
+	status, err := app.manager.GetTunnelStatus(name)
+	if err != nil {
+		log.Printf("터널 '%s' 주소 복사 실패: %v", name, err)
+		return
+	}
+	addresses := localAddresses(status.Config)
+	if len(addresses) == 0 {
+		return
+	}
+
+	text := strings.Join(addresses, "\n")
+	if err := copyToClipboard(text); err != nil {
+		log.Printf("클립보드 복사 실패: %v", err)
+		app.showError("클립보드 복사 실패", err.Error())
+		return
+	}
+	log.Printf("터널 '%s' 로컬 주소 복사: %s", name, strings.Join(addresses, ", "))
+}
+
+// viewLastError 터널의 마지막 오류와 최근 ssh 출력 표시
+func (app *TunnelApp) viewLastError(name string) {
+	status, err := app.manager.GetTunnelStatus(name)
+	if err != nil || status.LastError == "" {
+		return
+	}
+
+	message := status.LastError
+	if len(status.Output) > 0 {
+		message += "\n\nssh 출력:\n" + strings.Join(status.Output, "\n")
+	}
+	go app.showError(fmt.Sprintf("%s - 마지막 오류", name), message)
+}
+
+// localAddresses 터널에서 로컬로 접속할 주소 목록 (원격 포워딩은 SSH 서버 쪽에서 열리므로 제외)
+func localAddresses(cfg config.TunnelConfig) []string {
+	var addresses []string
+	for _, f := range cfg.GetForwards() {
+		switch f.GetType() {
+		case config.TypeLocal:
+			addresses = append(addresses, fmt.Sprintf("localhost:%d", f.LocalPort))
+		case config.TypeDynamic:
+			addresses = append(addresses, fmt.Sprintf("socks5://127.0.0.1:%d", f.LocalPort))
+		}
+	}
+	return addresses
+}
@@ -0,0 +1,121 @@
+package config
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// DefaultAPIListen 제어 API 기본 주소 (로컬에서만 접속 가능)
+const DefaultAPIListen = "127.0.0.1:7421"
+
+// APIUnixPrefix 제어 API를 Unix 소켓으로 열 때 listen 값 접두사 (unix:경로)
+const APIUnixPrefix = "unix:"
+
+// APITokenFileName token을 지정하지 않았을 때 설정 파일 옆에 자동 생성하는 토큰 파일 이름
+const APITokenFileName = "tunnels.token"
+
+// apiTokenSize 자동 생성 토큰 길이 (바이트)
+const apiTokenSize = 32
+
+// APIConfig 로컬 제어 API 설정 (스크립트에서 터널 시작/중지용, 기본값 비활성화)
+type APIConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Listen  string `yaml:"listen,omitempty"` // 127.0.0.1:포트 또는 unix:소켓경로 (기본값 127.0.0.1:7421)
+	Token   string `yaml:"token,omitempty"`  // 인증 토큰 (env:, file:, enc: 참조 가능, 미지정 시 tunnels.token 파일 자동 생성)
+}
+
+// Validate 제어 API 설정 유효성 검사
+func (a *APIConfig) Validate() error {
+	network, address := a.GetListen()
+	if network == "unix" {
+		// Windows의 Unix 소켓은 파일 권한으로 접근을 제한할 수 없고 named pipe는 지원하지 않음
+		if runtime.GOOS == "windows" {
+			return fmt.Errorf("Windows에서는 unix 소켓을 지원하지 않습니다. 127.0.0.1:포트를 사용하세요: %s", a.Listen)
+		}
+		if address == "" {
+			return fmt.Errorf("unix 소켓 경로가 필요합니다")
+		}
+		return nil
+	}
+
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("유효하지 않은 listen 주소: %s", address)
+	}
+	// 다른 컴퓨터에서 터널을 조작할 수 없도록 루프백 주소만 허용
+	if host != "localhost" {
+		ip := net.ParseIP(host)
+		if ip == nil || !ip.IsLoopback() {
+			return fmt.Errorf("listen 주소는 127.0.0.1 같은 루프백 주소여야 합니다: %s", address)
+		}
+	}
+	return nil
+}
+
+// GetListen 제어 API 리슨 네트워크와 주소 반환 (tcp 또는 unix)
+func (a *APIConfig) GetListen() (string, string) {
+	if strings.HasPrefix(a.Listen, APIUnixPrefix) {
+		return "unix", strings.TrimPrefix(a.Listen, APIUnixPrefix)
+	}
+	if a.Listen == "" {
+		return "tcp", DefaultAPIListen
+	}
+	return "tcp", a.Listen
+}
+
+// APITokenPath 설정 파일과 같은 디렉토리의 제어 API 토큰 파일 경로 반환
+func APITokenPath(configPath string) string {
+	return filepath.Join(filepath.Dir(configPath), APITokenFileName)
+}
+
+// ResolveAPIToken 제어 API 토큰 반환
+// token이 있으면 비밀 값 참조를 해석하고, 없으면 토큰 파일을 읽거나 새로 생성
+func (a *APIConfig) ResolveAPIToken(configPath string) (string, error) {
+	if a.Token != "" {
+		token, err := ResolveSecret(a.Token, configPath)
+		if err != nil {
+			return "", fmt.Errorf("api token: %v", err)
+		}
+		return token, nil
+	}
+
+	path := APITokenPath(configPath)
+	data, err := os.ReadFile(path)
+	if err == nil {
+		if token := strings.TrimSpace(string(data)); token != "" {
+			return token, nil
+		}
+		return "", fmt.Errorf("토큰 파일이 비어 있습니다: %s", path)
+	}
+	if !os.IsNotExist(err) {
+		return "", fmt.Errorf("토큰 파일 읽기 실패: %v", err)
+	}
+	return createAPIToken(path)
+}
+
+// createAPIToken 새 토큰 생성 후 파일로 저장 (소유자만 읽기/쓰기)
+func createAPIToken(path string) (string, error) {
+	buf := make([]byte, apiTokenSize)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("토큰 생성 실패: %v", err)
+	}
+	token := hex.EncodeToString(buf)
+
+	// 이미 있는 토큰을 덮어쓰지 않도록 O_EXCL 사용
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return "", fmt.Errorf("토큰 파일 생성 실패: %v", err)
+	}
+	defer f.Close()
+
+	if _, err := f.WriteString(token + "\n"); err != nil {
+		return "", fmt.Errorf("토큰 파일 저장 실패: %v", err)
+	}
+	return token, nil
+}
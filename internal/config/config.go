@@ -3,53 +3,143 @@ package config
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"runtime"
+	"strconv"
+	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
+// 터널 전송 방식
+const (
+	TransportExec   = "exec"   // OpenSSH 클라이언트(ssh) 프로세스 실행 (기본값)
+	TransportNative = "native" // Go 내장 SSH 클라이언트로 직접 연결
+)
+
+// 터널 종류
+const (
+	TypeLocal   = "local"   // 로컬 포트 포워딩 (-L, 기본값)
+	TypeRemote  = "remote"  // 원격 포트 포워딩 (-R)
+	TypeDynamic = "dynamic" // 동적 포트 포워딩, SOCKS5 프록시 (-D)
+)
+
+// 호스트 키 확인 정책
+const (
+	HostKeyAcceptNew = "accept-new" // 처음 보는 호스트 키는 known_hosts에 추가, 변경된 키는 거부 (기본값)
+	HostKeyStrict    = "strict"     // known_hosts에 등록된 키만 허용
+	HostKeyOff       = "off"        // 호스트 키 확인 안 함 (개발용)
+)
+
+// KnownHostsFileName 설정 파일 옆에 두는 앱 관리 known_hosts 파일 이름
+const KnownHostsFileName = "known_hosts"
+
+// PIDFileExt 설정 파일 옆에 두는 ssh 프로세스 PID 기록 파일 확장자 (설정 파일 이름.pids)
+const PIDFileExt = ".pids"
+
+// DefaultLocalHost 원격 포워딩의 기본 로컬 대상 호스트
+const DefaultLocalHost = "127.0.0.1"
+
+// ForwardConfig 포트 포워딩 하나의 설정
+//
+// type이 remote인 경우 의미가 반대가 됨:
+// SSH 서버의 remote_bind_address:remote_port로 들어온 연결이 local_host:local_port로 전달됨
+// type이 dynamic인 경우 local_port에 SOCKS5 프록시가 열리며 remote_host/remote_port는 사용하지 않음
+type ForwardConfig struct {
+	Name              string       `yaml:"name,omitempty"`
+	Type              string       `yaml:"type,omitempty"`       // local(기본값), remote 또는 dynamic
+	LocalHost         string       `yaml:"local_host,omitempty"` // remote 전용, 연결을 전달받을 로컬 호스트
+	LocalPort         int          `yaml:"local_port"`
+	RemoteHost        string       `yaml:"remote_host,omitempty"`
+	RemoteBindAddress string       `yaml:"remote_bind_address,omitempty"` // remote 전용, SSH 서버에서 리슨할 주소
+	RemotePort        int          `yaml:"remote_port,omitempty"`
+	Probe             *ProbeConfig `yaml:"probe,omitempty"` // 상태 확인 프로브 (미지정 시 로컬 포트 연결만 확인)
+}
+
+// JumpHostConfig 점프 호스트(ProxyJump) 하나의 설정
+type JumpHostConfig struct {
+	Host    string `yaml:"host"`
+	Port    int    `yaml:"port,omitempty"`     // 기본값 22
+	User    string `yaml:"user,omitempty"`     // 기본값 ssh_user
+	KeyPath string `yaml:"key_path,omitempty"` // 미지정 시 터널의 인증 방식 사용
+}
+
 // TunnelConfig SSH 터널 설정
+//
+// 포워딩이 하나면 type/local_port/remote_* 필드를 직접 사용하고,
+// 여러 개면 forwards 목록을 사용 (하나의 SSH 세션을 공유)
 type TunnelConfig struct {
-	Name        string `yaml:"name"`
-	LocalPort   int    `yaml:"local_port"`
-	RemoteHost  string `yaml:"remote_host"`
-	RemotePort  int    `yaml:"remote_port"`
-	SSHHost     string `yaml:"ssh_host"`
-	SSHPort     int    `yaml:"ssh_port"`
-	SSHUser     string `yaml:"ssh_user"`
-	SSHKeyPath  string `yaml:"ssh_key_path,omitempty"`
-	SSHPassword string `yaml:"ssh_password,omitempty"`
-	Enabled     bool   `yaml:"enabled"`
+	Name               string           `yaml:"name"`
+	Type               string           `yaml:"type,omitempty"` // local(기본값), remote 또는 dynamic
+	LocalHost          string           `yaml:"local_host,omitempty"`
+	LocalPort          int              `yaml:"local_port,omitempty"`
+	RemoteHost         string           `yaml:"remote_host,omitempty"`
+	RemoteBindAddress  string           `yaml:"remote_bind_address,omitempty"`
+	RemotePort         int              `yaml:"remote_port,omitempty"`
+	Forwards           []ForwardConfig  `yaml:"forwards,omitempty"`   // 여러 포워딩 (위 단일 포워딩 필드와 함께 사용 불가)
+	Probe              *ProbeConfig     `yaml:"probe,omitempty"`      // 단일 포워딩의 상태 확인 프로브
+	JumpHosts          []JumpHostConfig `yaml:"jump_hosts,omitempty"` // ssh_host 앞에 거쳐갈 점프 호스트 (순서대로)
+	SSHAlias           string           `yaml:"ssh_alias,omitempty"`  // ~/.ssh/config의 Host 별칭 (exec 전용, 접속 정보를 OpenSSH가 해석)
+	SSHHost            string           `yaml:"ssh_host,omitempty"`
+	SSHPort            int              `yaml:"ssh_port,omitempty"`
+	SSHUser            string           `yaml:"ssh_user,omitempty"`
+	SSHKeyPath         string           `yaml:"ssh_key_path,omitempty"`
+	SSHPassword        string           `yaml:"ssh_password,omitempty"`
+	UseAgent           bool             `yaml:"use_agent,omitempty"`            // SSH 에이전트 인증 사용 (SSH_AUTH_SOCK)
+	Transport          string           `yaml:"transport,omitempty"`            // exec(기본값) 또는 native
+	HostKeyPolicy      string           `yaml:"host_key_policy,omitempty"`      // accept-new(기본값), strict 또는 off
+	HostKeyFingerprint string           `yaml:"host_key_fingerprint,omitempty"` // 고정 지문 (SHA256:...), 지정 시 이 지문만 허용
+	Retry              *RetryConfig     `yaml:"retry,omitempty"`                // 터널별 재시도 정책 (전역 retry 설정보다 우선)
+	Enabled            bool             `yaml:"enabled"`
+
+	// KnownHostsFile 앱 관리 known_hosts 경로 (설정 파일에 쓰지 않고 LoadConfig에서 채움)
+	KnownHostsFile string `yaml:"-"`
+	// PIDFile exec 전송이 실행한 ssh 프로세스 PID 기록 파일 (설정 파일에 쓰지 않고 LoadConfig에서 채움)
+	PIDFile string `yaml:"-"`
+
+	sshPasswordRef string      // 설정 파일에 적힌 ssh_password 참조 (env:, file:, enc:), 저장 시 복원
+	secretErr      error       // 비밀 값 참조 해석 실패 원인 (Validate에서 보고)
+	globalRetry    RetryConfig // 전역 재시도 정책 (LoadConfig에서 채움)
 }
 
 // Config 전체 설정
 type Config struct {
-	Tunnels []TunnelConfig `yaml:"tunnels"`
-	CheckInterval int      `yaml:"check_interval"` // 초 단위
+	Tunnels       []TunnelConfig `yaml:"tunnels"`
+	CheckInterval int            `yaml:"check_interval"`  // 초 단위
+	Retry         RetryConfig    `yaml:"retry,omitempty"` // 전역 재시도 정책 (터널별 retry로 덮어쓸 수 있음)
+	API           APIConfig      `yaml:"api,omitempty"`   // 로컬 제어 API (기본값 비활성화)
+
+	doc    *yaml.Node // 설정 파일 문서 노드 (Validate에서 줄/열 위치를 찾을 때 사용, LoadConfig에서 채움)
+	source []byte     // 설정 파일 내용 (Validate에서 알 수 없는 키를 찾을 때 사용, LoadConfig에서 채움)
 }
 
 // DefaultConfig 기본 설정 생성
 func DefaultConfig() *Config {
 	return &Config{
-		Tunnels: []TunnelConfig{},
+		Tunnels:       []TunnelConfig{},
 		CheckInterval: 30,
 	}
 }
 
 // LoadConfig 설정 파일 로드
 func LoadConfig(configPath string) (*Config, error) {
+	data, err := ReadConfigFile(configPath)
+	if err != nil {
+		return nil, err
+	}
+	return ParseConfig(data, configPath)
+}
+
+// ReadConfigFile 설정 파일 내용 읽기 (파일이 없으면 기본 설정 파일을 만들고 그 내용 반환)
+func ReadConfigFile(configPath string) ([]byte, error) {
 	// 파일 존재 여부 및 권한 확인
 	_, err := os.Stat(configPath)
 	if os.IsNotExist(err) {
-		config := DefaultConfig()
-		if err := SaveConfig(config, configPath); err != nil {
+		if err := SaveConfig(DefaultConfig(), configPath); err != nil {
 			return nil, fmt.Errorf("기본 설정 파일 생성 실패: %v", err)
 		}
-		return config, nil
-	}
-
-	if err != nil {
+	} else if err != nil {
 		return nil, fmt.Errorf("설정 파일 접근 실패: %v", err)
 	}
 
@@ -57,32 +147,94 @@ func LoadConfig(configPath string) (*Config, error) {
 	if err != nil {
 		return nil, fmt.Errorf("설정 파일 읽기 실패: %v", err)
 	}
+	return data, nil
+}
 
+// ParseConfig ReadConfigFile로 읽은 설정 파일 내용 파싱
+// configPath는 known_hosts, PID 기록 파일, 비밀 값 파일 참조의 기준 경로
+func ParseConfig(data []byte, configPath string) (*Config, error) {
 	var config Config
 	if err := yaml.Unmarshal(data, &config); err != nil {
 		return nil, fmt.Errorf("설정 파일 파싱 실패: %v", err)
 	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err == nil {
+		config.doc = &doc
+		config.source = data
+	}
 
 	// 기본값 설정
 	if config.CheckInterval <= 0 {
 		config.CheckInterval = 30
 	}
 
+	// 호스트 키 확인에 사용할 known_hosts 경로 설정
+	knownHostsFile := KnownHostsPath(configPath)
+	pidFile := PIDFilePath(configPath)
+	for i := range config.Tunnels {
+		config.Tunnels[i].KnownHostsFile = knownHostsFile
+		config.Tunnels[i].PIDFile = pidFile
+	}
+
+	// 전역 재시도 정책 전달 (터널별 정책과 합쳐서 사용)
+	for i := range config.Tunnels {
+		config.Tunnels[i].globalRetry = config.Retry
+	}
+
+	// 비밀 값 참조 해석 (실패한 터널만 오류 상태가 되도록 Validate에서 보고)
+	for i := range config.Tunnels {
+		config.Tunnels[i].resolveSecrets(configPath)
+	}
+
 	return &config, nil
 }
 
-// SaveConfig 설정 파일 저장
-func SaveConfig(config *Config, configPath string) error {
-	data, err := yaml.Marshal(config)
+// KnownHostsPath 설정 파일과 같은 디렉토리의 앱 관리 known_hosts 경로 반환
+func KnownHostsPath(configPath string) string {
+	return filepath.Join(filepath.Dir(configPath), KnownHostsFileName)
+}
+
+// PIDFilePath 설정 파일과 같은 디렉토리의 ssh 프로세스 PID 기록 파일 경로 반환
+// 같은 디렉토리에서 다른 설정 파일로 실행한 인스턴스의 프로세스와 섞이지 않도록 설정 파일 이름을 사용 (tunnels.conf → tunnels.pids)
+func PIDFilePath(configPath string) string {
+	base := filepath.Base(configPath)
+	return filepath.Join(filepath.Dir(configPath), strings.TrimSuffix(base, filepath.Ext(base))+PIDFileExt)
+}
+
+// resolveSecrets ssh_password의 비밀 값 참조를 실제 값으로 변환
+func (t *TunnelConfig) resolveSecrets(configPath string) {
+	if !IsSecretRef(t.SSHPassword) {
+		return
+	}
+	t.sshPasswordRef = t.SSHPassword
+	password, err := ResolveSecret(t.SSHPassword, configPath)
 	if err != nil {
-		return fmt.Errorf("설정 마샬링 실패: %v", err)
+		t.SSHPassword = ""
+		t.secretErr = fmt.Errorf("ssh_password: %v", err)
+		return
+	}
+	t.SSHPassword = password
+}
+
+// SaveConfig 설정 전체를 새 파일로 저장 (기본 설정 파일 생성용)
+// 주석과 서식이 모두 사라지므로 기존 설정 파일을 고칠 때는 Editor 사용
+func SaveConfig(config *Config, configPath string) error {
+	// 해석된 비밀 값 대신 원래 참조를 저장
+	saved := *config
+	saved.Tunnels = make([]TunnelConfig, len(config.Tunnels))
+	for i, t := range config.Tunnels {
+		if t.sshPasswordRef != "" {
+			t.SSHPassword = t.sshPasswordRef
+		}
+		saved.Tunnels[i] = t
 	}
 
-	if err := os.WriteFile(configPath, data, 0644); err != nil {
-		return fmt.Errorf("설정 파일 저장 실패: %v", err)
+	data, err := yaml.Marshal(&saved)
+	if err != nil {
+		return fmt.Errorf("설정 마샬링 실패: %v", err)
 	}
 
-	return nil
+	return writeConfigFile(configPath, data)
 }
 
 // GetEnabledTunnels 활성화된 터널만 반환
@@ -96,33 +248,253 @@ func (c *Config) GetEnabledTunnels() []TunnelConfig {
 	return enabled
 }
 
-// Validate 터널 설정 유효성 검사
+// Validate 터널 설정 유효성 검사 (첫 번째 문제만 반환, 모든 문제는 Config.Validate에서 보고)
 func (t *TunnelConfig) Validate() error {
+	if err := firstFieldError(t.fieldErrors()); err != nil {
+		return err
+	}
+	if err := t.globalRetry.Validate(); err != nil {
+		return fmt.Errorf("전역 retry: %v", err)
+	}
+	return nil
+}
+
+// fieldErrors 터널 설정의 모든 문제 (전역 retry 설정은 Config.Validate에서 한 번만 보고하므로 제외)
+func (t *TunnelConfig) fieldErrors() []fieldError {
+	var errs []fieldError
 	if t.Name == "" {
-		return fmt.Errorf("터널 이름이 필요합니다")
+		errs = append(errs, newFieldError("name", "터널 이름이 필요합니다"))
 	}
-	if t.LocalPort <= 0 || t.LocalPort > 65535 {
-		return fmt.Errorf("유효하지 않은 로컬 포트: %d", t.LocalPort)
+	if t.secretErr != nil {
+		errs = append(errs, fieldError{path: []string{"ssh_password"}, err: t.secretErr})
 	}
-	if t.RemoteHost == "" {
-		return fmt.Errorf("원격 호스트가 필요합니다")
+	if len(t.Forwards) > 0 && t.hasInlineForward() {
+		errs = append(errs, newFieldError("forwards", "forwards 목록과 local_port/remote_* 필드를 함께 사용할 수 없습니다"))
 	}
-	if t.RemotePort <= 0 || t.RemotePort > 65535 {
-		return fmt.Errorf("유효하지 않은 원격 포트: %d", t.RemotePort)
+	if len(t.Forwards) > 0 && t.Probe != nil {
+		errs = append(errs, newFieldError("probe", "forwards 목록을 사용하는 경우 probe는 각 포워딩에 지정해야 합니다"))
 	}
-	if t.SSHHost == "" {
-		return fmt.Errorf("SSH 호스트가 필요합니다")
+
+	forwards := t.GetForwards()
+	localPorts := make(map[int]bool)
+	for i, f := range forwards {
+		// 단일 포워딩 터널은 터널 자체의 필드에 보고
+		label, path := "", []string(nil)
+		if len(t.Forwards) > 0 {
+			label, path = fmt.Sprintf("포워딩 #%d(%s)", i+1, f.Label()), []string{"forwards", strconv.Itoa(i)}
+		}
+		errs = append(errs, nestFieldErrors(f.fieldErrors(), label, path...)...)
+
+		// 같은 세션 안에서 로컬 포트 중복 확인 (remote는 로컬 포트에 리슨하지 않음)
+		if f.GetType() != TypeRemote {
+			if localPorts[f.LocalPort] {
+				errs = append(errs, fieldError{
+					path: append(path, "local_port"),
+					err:  fmt.Errorf("포워딩 #%d(%s): 로컬 포트 %d 중복", i+1, f.Label(), f.LocalPort),
+				})
+			}
+			localPorts[f.LocalPort] = true
+		}
 	}
-	if t.SSHPort <= 0 || t.SSHPort > 65535 {
-		return fmt.Errorf("유효하지 않은 SSH 포트: %d", t.SSHPort)
+
+	if t.SSHAlias != "" {
+		// 호스트, 포트, 사용자, 키 파일은 OpenSSH가 ssh_config에서 찾으므로 지정한 값만 확인
+		errs = append(errs, t.sshAliasFieldErrors()...)
+	} else {
+		if t.SSHHost == "" {
+			errs = append(errs, newFieldError("ssh_host", "SSH 호스트가 필요합니다"))
+		}
+		if t.SSHPort <= 0 || t.SSHPort > 65535 {
+			errs = append(errs, newFieldError("ssh_port", "유효하지 않은 SSH 포트: %d", t.SSHPort))
+		}
+		if t.SSHUser == "" {
+			errs = append(errs, newFieldError("ssh_user", "SSH 사용자명이 필요합니다"))
+		}
+		if t.SSHKeyPath == "" && t.SSHPassword == "" && !t.UseAgent {
+			errs = append(errs, newFieldError("", "SSH 키 파일, 패스워드 또는 에이전트 인증이 필요합니다"))
+		}
 	}
-	if t.SSHUser == "" {
-		return fmt.Errorf("SSH 사용자명이 필요합니다")
+	for i, j := range t.JumpHosts {
+		errs = append(errs, nestFieldErrors(j.fieldErrors(), fmt.Sprintf("점프 호스트 #%d", i+1), "jump_hosts", strconv.Itoa(i))...)
 	}
-	if t.SSHKeyPath == "" && t.SSHPassword == "" {
-		return fmt.Errorf("SSH 키 파일 또는 패스워드가 필요합니다")
+	switch t.HostKeyPolicy {
+	case "", HostKeyAcceptNew, HostKeyStrict, HostKeyOff:
+	default:
+		errs = append(errs, newFieldError("host_key_policy", "지원하지 않는 호스트 키 정책: %s (accept-new, strict 또는 off)", t.HostKeyPolicy))
 	}
-	return nil
+	if t.HostKeyFingerprint != "" {
+		if !strings.HasPrefix(t.HostKeyFingerprint, "SHA256:") {
+			errs = append(errs, newFieldError("host_key_fingerprint", "호스트 키 지문은 SHA256: 형식이어야 합니다: %s", t.HostKeyFingerprint))
+		}
+		// exec 방식은 지문을 미리 확인하기 위해 최종 호스트에 직접 접속해야 함
+		if len(t.JumpHosts) > 0 && t.GetTransport() == TransportExec {
+			errs = append(errs, newFieldError("host_key_fingerprint", "점프 호스트와 host_key_fingerprint를 함께 쓰려면 transport: native가 필요합니다"))
+		}
+	}
+	if t.Retry != nil {
+		errs = append(errs, nestFieldErrors(t.Retry.fieldErrors(), "retry", "retry")...)
+	}
+	switch t.Transport {
+	case "", TransportExec, TransportNative:
+	default:
+		errs = append(errs, newFieldError("transport", "지원하지 않는 전송 방식: %s (exec 또는 native)", t.Transport))
+	}
+	return errs
+}
+
+// sshAliasFieldErrors ssh_alias와 함께 쓸 수 없는 설정 확인 (별칭이 ssh_config에 있는지는 Config.Validate에서 확인)
+func (t *TunnelConfig) sshAliasFieldErrors() []fieldError {
+	var errs []fieldError
+	if t.GetTransport() != TransportExec {
+		errs = append(errs, newFieldError("transport", "ssh_alias를 쓰려면 transport: exec가 필요합니다"))
+	}
+	if t.SSHPort < 0 || t.SSHPort > 65535 {
+		errs = append(errs, newFieldError("ssh_port", "유효하지 않은 SSH 포트: %d", t.SSHPort))
+	}
+	if len(t.JumpHosts) > 0 {
+		errs = append(errs, newFieldError("jump_hosts", "ssh_alias를 쓰는 경우 점프 호스트는 ssh_config의 ProxyJump로 지정해야 합니다"))
+	}
+	// 지문 확인은 ssh_host에 직접 접속해야 하므로 OpenSSH가 해석하는 별칭과 함께 쓸 수 없음
+	if t.HostKeyFingerprint != "" {
+		errs = append(errs, newFieldError("host_key_fingerprint", "ssh_alias와 host_key_fingerprint를 함께 쓸 수 없습니다"))
+	}
+	// 실제 접속 호스트(HostName, ProxyJump)를 OpenSSH가 정하므로 패스워드를 물은 호스트가 맞는지 확인할 수 없음
+	if t.SSHPassword != "" || t.sshPasswordRef != "" {
+		errs = append(errs, newFieldError("ssh_password", "ssh_alias와 ssh_password를 함께 쓸 수 없습니다 (키 파일이나 에이전트 인증 사용)"))
+	}
+	return errs
+}
+
+// Validate 포워딩 설정 유효성 검사
+func (f *ForwardConfig) Validate() error {
+	return firstFieldError(f.fieldErrors())
+}
+
+// fieldErrors 포워딩 설정의 모든 문제
+func (f *ForwardConfig) fieldErrors() []fieldError {
+	var errs []fieldError
+	switch f.Type {
+	case "", TypeLocal, TypeRemote, TypeDynamic:
+	default:
+		errs = append(errs, newFieldError("type", "지원하지 않는 터널 종류: %s (local, remote 또는 dynamic)", f.Type))
+	}
+	if f.LocalPort <= 0 || f.LocalPort > 65535 {
+		errs = append(errs, newFieldError("local_port", "유효하지 않은 로컬 포트: %d", f.LocalPort))
+	}
+	// 원격 포워딩은 원격 호스트 대신 SSH 서버의 바인드 주소를 사용
+	if f.RemoteHost == "" && f.GetType() == TypeLocal {
+		errs = append(errs, newFieldError("remote_host", "원격 호스트가 필요합니다"))
+	}
+	// SOCKS5 프록시는 접속 대상을 클라이언트가 정하므로 원격 포트가 필요 없음
+	if (f.RemotePort <= 0 || f.RemotePort > 65535) && f.GetType() != TypeDynamic {
+		errs = append(errs, newFieldError("remote_port", "유효하지 않은 원격 포트: %d", f.RemotePort))
+	}
+	if f.Probe != nil {
+		errs = append(errs, nestFieldErrors(f.Probe.fieldErrors(f.GetType()), "probe", "probe")...)
+	}
+	return errs
+}
+
+// Validate 점프 호스트 설정 유효성 검사
+func (j *JumpHostConfig) Validate() error {
+	return firstFieldError(j.fieldErrors())
+}
+
+// fieldErrors 점프 호스트 설정의 모든 문제
+func (j *JumpHostConfig) fieldErrors() []fieldError {
+	var errs []fieldError
+	if j.Host == "" {
+		errs = append(errs, newFieldError("host", "호스트가 필요합니다"))
+	}
+	if j.Port < 0 || j.Port > 65535 {
+		errs = append(errs, newFieldError("port", "유효하지 않은 포트: %d", j.Port))
+	}
+	return errs
+}
+
+// GetPort 점프 호스트 포트 반환 (미지정 시 22)
+func (j *JumpHostConfig) GetPort() int {
+	if j.Port == 0 {
+		return 22
+	}
+	return j.Port
+}
+
+// GetUser 점프 호스트 사용자 반환 (미지정 시 터널의 ssh_user)
+func (j *JumpHostConfig) GetUser(defaultUser string) string {
+	if j.User == "" {
+		return defaultUser
+	}
+	return j.User
+}
+
+// GetForwards 포워딩 목록 반환 (forwards가 없으면 단일 포워딩 필드로 구성)
+func (t *TunnelConfig) GetForwards() []ForwardConfig {
+	if len(t.Forwards) > 0 {
+		return t.Forwards
+	}
+	return []ForwardConfig{{
+		Type:              t.Type,
+		LocalHost:         t.LocalHost,
+		LocalPort:         t.LocalPort,
+		RemoteHost:        t.RemoteHost,
+		RemoteBindAddress: t.RemoteBindAddress,
+		RemotePort:        t.RemotePort,
+		Probe:             t.Probe,
+	}}
+}
+
+// hasInlineForward 단일 포워딩 필드가 하나라도 설정되어 있는지 확인
+func (t *TunnelConfig) hasInlineForward() bool {
+	return t.Type != "" || t.LocalHost != "" || t.LocalPort != 0 ||
+		t.RemoteHost != "" || t.RemoteBindAddress != "" || t.RemotePort != 0
+}
+
+// GetType 포워딩 종류 반환 (미지정 시 local)
+func (f *ForwardConfig) GetType() string {
+	if f.Type == "" {
+		return TypeLocal
+	}
+	return f.Type
+}
+
+// GetLocalHost 원격 포워딩의 로컬 대상 호스트 반환 (미지정 시 127.0.0.1)
+func (f *ForwardConfig) GetLocalHost() string {
+	if f.LocalHost == "" {
+		return DefaultLocalHost
+	}
+	return f.LocalHost
+}
+
+// Label 포워딩 표시 이름 (name이 없으면 L:8080, R:9000, D:1080 형식)
+func (f *ForwardConfig) Label() string {
+	if f.Name != "" {
+		return f.Name
+	}
+	switch f.GetType() {
+	case TypeRemote:
+		return fmt.Sprintf("R:%d", f.RemotePort)
+	case TypeDynamic:
+		return fmt.Sprintf("D:%d", f.LocalPort)
+	default:
+		return fmt.Sprintf("L:%d", f.LocalPort)
+	}
+}
+
+// GetHostKeyPolicy 호스트 키 확인 정책 반환 (미지정 시 accept-new)
+func (t *TunnelConfig) GetHostKeyPolicy() string {
+	if t.HostKeyPolicy == "" {
+		return HostKeyAcceptNew
+	}
+	return t.HostKeyPolicy
+}
+
+// GetTransport 전송 방식 반환 (미지정 시 exec)
+func (t *TunnelConfig) GetTransport() string {
+	if t.Transport == "" {
+		return TransportExec
+	}
+	return t.Transport
 }
 
 // GetCheckIntervalDuration 체크 간격을 Duration으로 반환
@@ -130,20 +502,33 @@ func (c *Config) GetCheckIntervalDuration() time.Duration {
 	return time.Duration(c.CheckInterval) * time.Second
 }
 
-// CheckKeyFilePermissions 키 파일 권한 확인
+// CheckKeyFilePermissions 키 파일 권한 확인 (점프 호스트 키 포함)
 func (t *TunnelConfig) CheckKeyFilePermissions() error {
+	if err := checkKeyFile(t.SSHKeyPath); err != nil {
+		return err
+	}
+	for i, j := range t.JumpHosts {
+		if err := checkKeyFile(j.KeyPath); err != nil {
+			return fmt.Errorf("점프 호스트 #%d: %v", i+1, err)
+		}
+	}
+	return nil
+}
+
+// checkKeyFile 키 파일 하나의 존재 여부 및 권한 확인
+func checkKeyFile(keyPath string) error {
 	// 키 파일이 설정되지 않은 경우 (패스워드 인증 사용) 패스
-	if t.SSHKeyPath == "" {
+	if keyPath == "" {
 		return nil
 	}
 
 	// 파일 존재 여부 확인
-	info, err := os.Stat(t.SSHKeyPath)
+	info, err := os.Stat(keyPath)
 	if os.IsNotExist(err) {
-		return fmt.Errorf("SSH 키 파일이 존재하지 않습니다: %s", t.SSHKeyPath)
+		return fmt.Errorf("SSH 키 파일이 존재하지 않습니다: %s", keyPath)
 	}
 	if err != nil {
-		return fmt.Errorf("SSH 키 파일 접근 실패: %s, 오류: %v", t.SSHKeyPath, err)
+		return fmt.Errorf("SSH 키 파일 접근 실패: %s, 오류: %v", keyPath, err)
 	}
 
 	// 파일 권한 확인
@@ -153,28 +538,15 @@ func (t *TunnelConfig) CheckKeyFilePermissions() error {
 	if runtime.GOOS == "windows" {
 		// Windows에서는 파일이 읽기 가능한지만 확인
 		if mode&0400 == 0 {
-			return fmt.Errorf("SSH 키 파일 읽기 권한이 없습니다: %s (권한: %s)", t.SSHKeyPath, mode.String())
+			return fmt.Errorf("SSH 키 파일 읽기 권한이 없습니다: %s (권한: %s)", keyPath, mode.String())
 		}
 	} else {
 		// Unix/Linux 시스템에서는 파일 권한이 너무 넓으면 경고
 		// SSH는 보안상 키 파일의 권한이 600 (소유자만 읽기/쓰기)이어야 함
 		if mode&0077 != 0 {
-			return fmt.Errorf("SSH 키 파일 권한이 너무 넓습니다: %s (권한: %s, 권장: 600)", t.SSHKeyPath, mode.String())
+			return fmt.Errorf("SSH 키 파일 권한이 너무 넓습니다: %s (권한: %s, 권장: 600)", keyPath, mode.String())
 		}
 	}
 
 	return nil
 }
-
-// CheckAllEnabledKeyFilePermissions 모든 활성화된 터널의 키 파일 권한 확인
-func (c *Config) CheckAllEnabledKeyFilePermissions() []error {
-	var errors []error
-
-	for _, tunnel := range c.GetEnabledTunnels() {
-		if err := tunnel.CheckKeyFilePermissions(); err != nil {
-			errors = append(errors, fmt.Errorf("터널 '%s': %v", tunnel.Name, err))
-		}
-	}
-
-	return errors
-}
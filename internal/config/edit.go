@@ -0,0 +1,398 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Editor 설정 파일 편집기
+//
+// 구조체로 다시 마샬링하면 주석과 순서, 서식이 모두 사라지므로
+// yaml.Node로 바꿀 위치만 찾고 원본 텍스트에서 해당 줄만 고침
+type Editor struct {
+	path    string
+	data    []byte
+	doc     yaml.Node
+	newline string // 원본 파일의 줄바꿈 (\n 또는 \r\n)
+}
+
+// OpenEditor 설정 파일을 읽어서 편집기 생성
+func OpenEditor(configPath string) (*Editor, error) {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("설정 파일 읽기 실패: %v", err)
+	}
+
+	e := &Editor{path: configPath, newline: "\n"}
+	if bytes.Contains(data, []byte("\r\n")) {
+		e.newline = "\r\n"
+	}
+	if err := e.load(data); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// SetTunnelEnabled 설정 파일에서 터널 하나의 enabled 값만 바꿔서 저장 (주석, 순서, 서식 유지)
+func SetTunnelEnabled(configPath, name string, enabled bool) error {
+	e, err := OpenEditor(configPath)
+	if err != nil {
+		return err
+	}
+	if err := e.SetTunnelEnabled(name, enabled); err != nil {
+		return err
+	}
+	return e.Save()
+}
+
+// Bytes 편집한 설정 파일 내용
+func (e *Editor) Bytes() []byte {
+	return e.data
+}
+
+// Save 편집한 내용을 설정 파일에 저장
+func (e *Editor) Save() error {
+	return writeConfigFile(e.path, e.data)
+}
+
+// SetTunnelEnabled 터널 활성화/비활성화
+func (e *Editor) SetTunnelEnabled(name string, enabled bool) error {
+	return e.SetTunnelField(name, "enabled", enabled)
+}
+
+// SetTunnelField 터널 하나의 스칼라 값 변경 (local_port 등, 키가 없으면 name 다음 줄에 추가)
+// 기존 값의 따옴표 형식과 같은 줄의 주석은 그대로 유지
+func (e *Editor) SetTunnelField(name, key string, value interface{}) error {
+	item, err := e.findTunnel(name)
+	if err != nil {
+		return err
+	}
+	if item.Style&yaml.FlowStyle != 0 {
+		return fmt.Errorf("터널 '%s'이 한 줄 형식({...})으로 작성되어 있어 수정할 수 없습니다", name)
+	}
+
+	lines := e.lines()
+	if _, valueNode := mappingValue(item, key); valueNode != nil {
+		if valueNode.Kind != yaml.ScalarNode || valueNode.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+			return fmt.Errorf("터널 '%s'의 %s 값은 한 줄 값이 아니어서 수정할 수 없습니다", name, key)
+		}
+		text, err := formatScalar(value, valueNode.Style)
+		if err != nil {
+			return fmt.Errorf("%s: %v", key, err)
+		}
+		index := valueNode.Line - 1
+		lines[index] = replaceScalar(lines[index], valueNode.Column, text)
+		return e.apply(lines)
+	}
+
+	text, err := formatScalar(value, yaml.DoubleQuotedStyle)
+	if err != nil {
+		return fmt.Errorf("%s: %v", key, err)
+	}
+	// 새 키는 name 다음 줄에 같은 들여쓰기로 추가
+	nameKey, _ := mappingValue(item, "name")
+	line := strings.Repeat(" ", nameKey.Column-1) + key + ": " + text
+	return e.apply(e.insertLines(lines, nameKey.Line, []string{line}))
+}
+
+// AddTunnel 터널 목록 끝에 새 터널 추가 (기존 항목의 들여쓰기를 따르고, 문자열 값은 큰따옴표로 저장)
+func (e *Editor) AddTunnel(tunnel TunnelConfig) error {
+	if tunnel.Name == "" {
+		return fmt.Errorf("터널 이름이 필요합니다")
+	}
+	if _, err := e.findTunnel(tunnel.Name); err == nil {
+		return fmt.Errorf("이미 있는 터널 이름: %s", tunnel.Name)
+	}
+	if tunnel.sshPasswordRef != "" {
+		tunnel.SSHPassword = tunnel.sshPasswordRef
+	}
+
+	body, err := renderMapping(tunnel)
+	if err != nil {
+		return err
+	}
+
+	lines := e.lines()
+	dashIndent, keyIndent := "  ", "    "
+	after := 0 // 이 줄 다음에 추가 (1부터, 0이면 파일 끝)
+
+	root := e.doc.Content[0]
+	tunnelsKey, tunnels := mappingValue(root, "tunnels")
+	switch {
+	case tunnels == nil:
+		// tunnels 키가 없으면 파일 끝에 추가
+		lines = append(e.terminate(lines), "tunnels:"+e.newline)
+		after = len(lines)
+	case tunnels.Kind == yaml.SequenceNode && len(tunnels.Content) > 0:
+		if tunnels.Style&yaml.FlowStyle != 0 {
+			return fmt.Errorf("tunnels 목록이 한 줄 형식([...])으로 작성되어 있어 추가할 수 없습니다")
+		}
+		first := tunnels.Content[0]
+		keyIndent = strings.Repeat(" ", first.Column-1)
+		if dash := strings.LastIndexByte(columnPrefix(lines[first.Line-1], first.Column), '-'); dash >= 0 {
+			dashIndent = strings.Repeat(" ", dash)
+		}
+		last := tunnels.Content[len(tunnels.Content)-1]
+		after = lastLine(last)
+	case tunnels.Kind == yaml.SequenceNode || (tunnels.Kind == yaml.ScalarNode && tunnels.Tag == "!!null"):
+		// tunnels: [] 또는 tunnels: (빈 값)이면 값을 지우고 다음 줄부터 목록 작성
+		if tunnels.Kind == yaml.SequenceNode {
+			index := tunnels.Line - 1
+			lines[index] = replaceScalar(lines[index], tunnels.Column, "")
+			lines[index] = strings.TrimRight(strings.TrimRight(lines[index], "\r\n"), " \t") + e.newline
+		}
+		after = tunnelsKey.Line
+	default:
+		return fmt.Errorf("tunnels 항목이 목록 형식이 아닙니다")
+	}
+
+	var added []string
+	// 항목 사이를 빈 줄로 구분하는 파일이면 같은 형식 유지
+	if tunnels != nil && len(tunnels.Content) >= 2 {
+		for i := lastLine(tunnels.Content[0]); i < tunnels.Content[1].Line-1; i++ {
+			if strings.TrimSpace(lines[i]) == "" {
+				added = append(added, "")
+				break
+			}
+		}
+	}
+	for i, line := range strings.Split(strings.TrimSuffix(body, "\n"), "\n") {
+		if i == 0 {
+			added = append(added, dashIndent+"- "+line)
+		} else {
+			added = append(added, keyIndent+line)
+		}
+	}
+
+	if err := e.apply(e.insertLines(lines, after, added)); err != nil {
+		return err
+	}
+	if _, err := e.findTunnel(tunnel.Name); err != nil {
+		return fmt.Errorf("터널 추가 결과를 확인할 수 없습니다: %v", err)
+	}
+	return nil
+}
+
+// load 설정 파일 내용을 파싱해서 편집기 상태 갱신
+func (e *Editor) load(data []byte) error {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("설정 파일 파싱 실패: %v", err)
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return fmt.Errorf("설정 파일 형식이 올바르지 않습니다")
+	}
+	e.data = data
+	e.doc = doc
+	return nil
+}
+
+// apply 고친 줄들을 다시 파싱해서 반영 (결과가 올바른 YAML이 아니면 바꾸지 않음)
+func (e *Editor) apply(lines []string) error {
+	return e.load([]byte(strings.Join(lines, "")))
+}
+
+// lines 줄바꿈을 포함한 줄 단위로 나눈 현재 내용
+func (e *Editor) lines() []string {
+	return strings.SplitAfter(string(e.data), "\n")
+}
+
+// terminate 마지막 줄이 줄바꿈으로 끝나도록 보정 (빈 마지막 조각은 제거)
+func (e *Editor) terminate(lines []string) []string {
+	if n := len(lines); n > 0 && lines[n-1] == "" {
+		lines = lines[:n-1]
+	}
+	if n := len(lines); n > 0 && !strings.HasSuffix(lines[n-1], "\n") {
+		lines[n-1] += e.newline
+	}
+	return lines
+}
+
+// insertLines after번째 줄(1부터) 다음에 새 줄들 추가
+func (e *Editor) insertLines(lines []string, after int, added []string) []string {
+	lines = e.terminate(lines)
+	if after > len(lines) {
+		after = len(lines)
+	}
+	inserted := make([]string, len(added))
+	for i, line := range added {
+		inserted[i] = line + e.newline
+	}
+
+	result := make([]string, 0, len(lines)+len(inserted))
+	result = append(result, lines[:after]...)
+	result = append(result, inserted...)
+	return append(result, lines[after:]...)
+}
+
+// findTunnel 이름이 name인 터널 항목(매핑 노드) 찾기
+func (e *Editor) findTunnel(name string) (*yaml.Node, error) {
+	_, tunnels := mappingValue(e.doc.Content[0], "tunnels")
+	if tunnels == nil || tunnels.Kind != yaml.SequenceNode {
+		return nil, fmt.Errorf("설정 파일에 tunnels 항목이 없습니다")
+	}
+
+	for _, item := range tunnels.Content {
+		if item.Kind != yaml.MappingNode {
+			continue
+		}
+		if _, value := mappingValue(item, "name"); value != nil && value.Value == name {
+			return item, nil
+		}
+	}
+	return nil, fmt.Errorf("설정 파일에 없는 터널: %s", name)
+}
+
+// mappingValue 매핑 노드에서 키와 값 노드 찾기 (없으면 nil)
+func mappingValue(node *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	if node.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i], node.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+// lastLine 노드와 하위 노드가 차지하는 마지막 줄 번호 (1부터)
+func lastLine(node *yaml.Node) int {
+	line := node.Line
+	if node.Kind == yaml.ScalarNode && node.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+		line += strings.Count(strings.TrimSuffix(node.Value, "\n"), "\n") + 1
+	}
+	for _, child := range node.Content {
+		if l := lastLine(child); l > line {
+			line = l
+		}
+	}
+	return line
+}
+
+// renderMapping 구조체를 YAML 매핑으로 변환 (2칸 들여쓰기, 문자열 값은 큰따옴표)
+func renderMapping(value interface{}) (string, error) {
+	var node yaml.Node
+	if err := node.Encode(value); err != nil {
+		return "", fmt.Errorf("설정 변환 실패: %v", err)
+	}
+	quoteStrings(&node)
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return "", fmt.Errorf("설정 변환 실패: %v", err)
+	}
+	encoder.Close()
+	return buf.String(), nil
+}
+
+// quoteStrings 매핑 값 중 문자열을 큰따옴표 형식으로 지정 (예제 설정 파일과 같은 형식)
+func quoteStrings(node *yaml.Node) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 1; i < len(node.Content); i += 2 {
+			quoteStrings(node.Content[i])
+		}
+	case yaml.SequenceNode:
+		for _, child := range node.Content {
+			quoteStrings(child)
+		}
+	case yaml.ScalarNode:
+		if node.Tag == "!!str" && !strings.Contains(node.Value, "\n") {
+			node.Style = yaml.DoubleQuotedStyle
+		}
+	}
+}
+
+// formatScalar 값을 한 줄 YAML 스칼라로 변환 (문자열이면 style의 따옴표 형식 유지)
+func formatScalar(value interface{}, style yaml.Style) (string, error) {
+	var node yaml.Node
+	if err := node.Encode(value); err != nil {
+		return "", err
+	}
+	if node.Kind != yaml.ScalarNode {
+		return "", fmt.Errorf("한 줄 값만 지정할 수 있습니다")
+	}
+	if node.Tag == "!!str" {
+		node.Style = style & (yaml.DoubleQuotedStyle | yaml.SingleQuotedStyle)
+	}
+
+	out, err := yaml.Marshal(&node)
+	if err != nil {
+		return "", err
+	}
+	text := strings.TrimSuffix(string(out), "\n")
+	if strings.Contains(text, "\n") {
+		return "", fmt.Errorf("여러 줄 값은 지정할 수 없습니다")
+	}
+	return text, nil
+}
+
+// columnPrefix 줄에서 column(1부터, 문자 단위) 앞부분
+func columnPrefix(line string, column int) string {
+	offset := 0
+	for i := 1; i < column && offset < len(line); i++ {
+		_, size := utf8.DecodeRuneInString(line[offset:])
+		offset += size
+	}
+	return line[:offset]
+}
+
+// replaceScalar 줄에서 column 위치의 한 줄 값(스칼라 또는 [])을 value로 교체 (뒤의 주석은 유지)
+func replaceScalar(line string, column int, value string) string {
+	start := len(columnPrefix(line, column))
+	end := scalarEnd(line, start)
+	return line[:start] + value + line[end:]
+}
+
+// scalarEnd start에서 시작하는 값이 끝나는 위치
+func scalarEnd(line string, start int) int {
+	if start >= len(line) {
+		return start
+	}
+
+	switch line[start] {
+	case '"':
+		// 백슬래시 이스케이프 건너뜀
+		for i := start + 1; i < len(line); i++ {
+			if line[i] == '\\' {
+				i++
+			} else if line[i] == '"' {
+				return i + 1
+			}
+		}
+	case '\'':
+		// 작은따옴표 안의 ''는 이스케이프된 작은따옴표
+		for i := start + 1; i < len(line); i++ {
+			if line[i] == '\'' {
+				if i+1 < len(line) && line[i+1] == '\'' {
+					i++
+					continue
+				}
+				return i + 1
+			}
+		}
+	case '[':
+		// 한 줄 안의 빈 목록 ([])
+		if i := strings.IndexByte(line[start:], ']'); i >= 0 {
+			return start + i + 1
+		}
+	}
+
+	// 따옴표 없는 값은 주석(" #") 또는 줄 끝까지 (끝의 공백 제외)
+	end := len(line)
+	if i := strings.Index(line[start:], " #"); i >= 0 {
+		end = start + i
+	}
+	if i := strings.Index(line[start:end], "\t#"); i >= 0 {
+		end = start + i
+	}
+	return start + len(strings.TrimRight(line[start:end], " \t\r\n"))
+}
@@ -0,0 +1,317 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// editFixture 주석, 한 줄 형식 매핑, 여러 따옴표 형식이 섞인 설정 파일
+const editFixture = `# 터널 설정
+check_interval: 30 # 초
+
+retry: {initial_delay: 5, max_delay: 60}
+
+tunnels:
+  # 데이터베이스
+  - name: "database"
+    local_port: 5432 # 로컬 포트
+    remote_host: 'db.internal'
+    remote_port: 5432
+    ssh_host: "bastion.example.com"
+    ssh_port: 22
+    ssh_user: deploy
+    ssh_key_path: "C:\\keys\\id_rsa" # 이스케이프된 경로
+    enabled: true
+
+  - name: web
+    forwards:
+      - {name: "http", local_port: 8080, remote_host: "web", remote_port: 80}
+      - name: admin
+        local_port: 8443
+        remote_host: web
+        remote_port: 443
+    ssh_host: bastion.example.com
+    ssh_user: "deploy"
+    enabled: false  # 나중에 활성화
+
+  - {name: "inline", local_port: 6379, remote_host: redis, remote_port: 6379, ssh_host: bastion, ssh_user: deploy, enabled: false}
+`
+
+// editNewlines 줄바꿈 형식별로 같은 테스트 실행
+var editNewlines = []struct {
+	name    string
+	newline string
+}{
+	{"LF", "\n"},
+	{"CRLF", "\r\n"},
+}
+
+// newTestEditor 줄바꿈을 바꾼 fixture로 편집기 생성
+func newTestEditor(t *testing.T, newline string) (*Editor, []byte) {
+	t.Helper()
+	data := []byte(strings.ReplaceAll(editFixture, "\n", newline))
+	e := &Editor{newline: newline}
+	if err := e.load(data); err != nil {
+		t.Fatalf("fixture 파싱 실패: %v", err)
+	}
+	return e, data
+}
+
+// parseTestConfig 설정 파일 내용을 Config로 파싱
+func parseTestConfig(t *testing.T, data []byte) Config {
+	t.Helper()
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		t.Fatalf("편집 결과 파싱 실패: %v\n%s", err, data)
+	}
+	return cfg
+}
+
+// changedLines 앞뒤로 같은 줄을 제외하고 달라진 구간 반환 (line은 구간이 시작하는 줄 번호, 1부터)
+func changedLines(before, after []byte) (line int, removed, added []string) {
+	a := strings.SplitAfter(string(before), "\n")
+	b := strings.SplitAfter(string(after), "\n")
+
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	return prefix + 1, a[prefix : len(a)-suffix], b[prefix : len(b)-suffix]
+}
+
+// assertChanged 편집 결과에서 line번째 줄부터 removed가 added로 바뀌고 나머지는 바이트 단위로 같은지 확인
+func assertChanged(t *testing.T, before, after []byte, line int, removed, added []string) {
+	t.Helper()
+	gotLine, gotRemoved, gotAdded := changedLines(before, after)
+	if gotLine != line || strings.Join(gotRemoved, "") != strings.Join(removed, "") || strings.Join(gotAdded, "") != strings.Join(added, "") {
+		t.Fatalf("바뀐 줄이 다름\n got: %d줄 %q -> %q\nwant: %d줄 %q -> %q\n%s", gotLine, gotRemoved, gotAdded, line, removed, added, after)
+	}
+	// 새로 쓴 줄도 원본 파일의 줄바꿈 형식을 따라야 함
+	crlf := strings.Contains(string(before), "\r\n")
+	for i, l := range strings.SplitAfter(string(after), "\n") {
+		if strings.HasSuffix(l, "\n") && strings.HasSuffix(l, "\r\n") != crlf {
+			t.Fatalf("%d번째 줄의 줄바꿈 형식이 원본과 다름: %q", i+1, l)
+		}
+	}
+}
+
+func TestEditorSetTunnelEnabled(t *testing.T) {
+	for _, nl := range editNewlines {
+		t.Run(nl.name, func(t *testing.T) {
+			e, before := newTestEditor(t, nl.newline)
+			want := parseTestConfig(t, before)
+
+			if err := e.SetTunnelEnabled("web", true); err != nil {
+				t.Fatalf("SetTunnelEnabled 실패: %v", err)
+			}
+			if err := e.SetTunnelEnabled("database", false); err != nil {
+				t.Fatalf("SetTunnelEnabled 실패: %v", err)
+			}
+			after := e.Bytes()
+
+			// 두 줄만 바뀌므로 database의 enabled 줄을 먼저 확인하고, 되돌린 뒤 web의 enabled 줄 확인
+			lines := strings.SplitAfter(string(after), "\n")
+			if lines[15] != "    enabled: false"+nl.newline {
+				t.Fatalf("database enabled 줄이 다름: %q", lines[15])
+			}
+			lines[15] = "    enabled: true" + nl.newline
+			assertChanged(t, before, []byte(strings.Join(lines, "")), 27,
+				[]string{"    enabled: false  # 나중에 활성화" + nl.newline},
+				[]string{"    enabled: true  # 나중에 활성화" + nl.newline})
+
+			want.Tunnels[0].Enabled = false
+			want.Tunnels[1].Enabled = true
+			if got := parseTestConfig(t, after); !reflect.DeepEqual(got, want) {
+				t.Fatalf("파싱 결과가 다름\n got: %+v\nwant: %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestEditorSetTunnelField(t *testing.T) {
+	tests := []struct {
+		name    string
+		tunnel  string
+		key     string
+		value   interface{}
+		line    int
+		removed string
+		added   string
+		update  func(cfg *Config)
+	}{
+		{
+			name:    "작은따옴표 유지",
+			tunnel:  "database",
+			key:     "remote_host",
+			value:   "db2.internal",
+			line:    10,
+			removed: "    remote_host: 'db.internal'",
+			added:   "    remote_host: 'db2.internal'",
+			update:  func(cfg *Config) { cfg.Tunnels[0].RemoteHost = "db2.internal" },
+		},
+		{
+			name:    "큰따옴표 유지",
+			tunnel:  "database",
+			key:     "ssh_host",
+			value:   `new "bastion"`,
+			line:    12,
+			removed: `    ssh_host: "bastion.example.com"`,
+			added:   `    ssh_host: "new \"bastion\""`,
+			update:  func(cfg *Config) { cfg.Tunnels[0].SSHHost = `new "bastion"` },
+		},
+		{
+			name:    "이스케이프된 값과 주석 유지",
+			tunnel:  "database",
+			key:     "ssh_key_path",
+			value:   `D:\keys\id_ed25519`,
+			line:    15,
+			removed: `    ssh_key_path: "C:\\keys\\id_rsa" # 이스케이프된 경로`,
+			added:   `    ssh_key_path: "D:\\keys\\id_ed25519" # 이스케이프된 경로`,
+			update:  func(cfg *Config) { cfg.Tunnels[0].SSHKeyPath = `D:\keys\id_ed25519` },
+		},
+		{
+			name:    "숫자 값과 주석 유지",
+			tunnel:  "database",
+			key:     "local_port",
+			value:   15432,
+			line:    9,
+			removed: "    local_port: 5432 # 로컬 포트",
+			added:   "    local_port: 15432 # 로컬 포트",
+			update:  func(cfg *Config) { cfg.Tunnels[0].LocalPort = 15432 },
+		},
+		{
+			name:    "따옴표 없는 값",
+			tunnel:  "web",
+			key:     "ssh_host",
+			value:   "jump.example.com",
+			line:    25,
+			removed: "    ssh_host: bastion.example.com",
+			added:   "    ssh_host: jump.example.com",
+			update:  func(cfg *Config) { cfg.Tunnels[1].SSHHost = "jump.example.com" },
+		},
+		{
+			name:   "없는 키는 name 다음 줄에 추가",
+			tunnel: "web",
+			key:    "transport",
+			value:  "native",
+			line:   19,
+			added:  `    transport: "native"`,
+			update: func(cfg *Config) { cfg.Tunnels[1].Transport = "native" },
+		},
+	}
+
+	for _, nl := range editNewlines {
+		for _, tt := range tests {
+			t.Run(nl.name+"/"+tt.name, func(t *testing.T) {
+				e, before := newTestEditor(t, nl.newline)
+				want := parseTestConfig(t, before)
+
+				if err := e.SetTunnelField(tt.tunnel, tt.key, tt.value); err != nil {
+					t.Fatalf("SetTunnelField 실패: %v", err)
+				}
+				after := e.Bytes()
+
+				var removed []string
+				if tt.removed != "" {
+					removed = []string{tt.removed + nl.newline}
+				}
+				assertChanged(t, before, after, tt.line, removed, []string{tt.added + nl.newline})
+
+				tt.update(&want)
+				if got := parseTestConfig(t, after); !reflect.DeepEqual(got, want) {
+					t.Fatalf("파싱 결과가 다름\n got: %+v\nwant: %+v", got, want)
+				}
+			})
+		}
+	}
+}
+
+func TestEditorSetTunnelFieldFlowStyle(t *testing.T) {
+	e, before := newTestEditor(t, "\n")
+
+	// 한 줄 형식 터널은 줄 단위로 고칠 수 없으므로 오류를 내고 내용은 그대로 둠
+	if err := e.SetTunnelEnabled("inline", true); err == nil {
+		t.Fatal("한 줄 형식 터널 수정이 오류 없이 끝남")
+	}
+	if string(e.Bytes()) != string(before) {
+		t.Fatalf("오류가 난 편집이 내용을 바꿈\n%s", e.Bytes())
+	}
+}
+
+func TestEditorAddTunnel(t *testing.T) {
+	added := TunnelConfig{
+		Name:       "cache",
+		LocalPort:  6380,
+		RemoteHost: "cache.internal",
+		RemotePort: 6379,
+		SSHHost:    "bastion.example.com",
+		SSHPort:    22,
+		SSHUser:    "deploy",
+		UseAgent:   true,
+		Enabled:    true,
+	}
+
+	for _, nl := range editNewlines {
+		t.Run(nl.name, func(t *testing.T) {
+			e, before := newTestEditor(t, nl.newline)
+			want := parseTestConfig(t, before)
+
+			if err := e.AddTunnel(added); err != nil {
+				t.Fatalf("AddTunnel 실패: %v", err)
+			}
+			after := e.Bytes()
+
+			// 마지막 항목 다음에 기존 항목처럼 빈 줄로 구분하고 같은 들여쓰기로 추가
+			var lines []string
+			for _, line := range []string{
+				"",
+				`  - name: "cache"`,
+				"    local_port: 6380",
+				`    remote_host: "cache.internal"`,
+				"    remote_port: 6379",
+				`    ssh_host: "bastion.example.com"`,
+				"    ssh_port: 22",
+				`    ssh_user: "deploy"`,
+				"    use_agent: true",
+				"    enabled: true",
+			} {
+				lines = append(lines, line+nl.newline)
+			}
+			assertChanged(t, before, after, 30, nil, lines)
+
+			want.Tunnels = append(want.Tunnels, added)
+			if got := parseTestConfig(t, after); !reflect.DeepEqual(got, want) {
+				t.Fatalf("파싱 결과가 다름\n got: %+v\nwant: %+v", got, want)
+			}
+
+			if err := e.AddTunnel(added); err == nil {
+				t.Fatal("같은 이름의 터널 추가가 오류 없이 끝남")
+			}
+		})
+	}
+}
+
+func TestEditorAddTunnelEmptyList(t *testing.T) {
+	e := &Editor{newline: "\n"}
+	before := []byte("# 설정\ntunnels: [] # 비어 있음\ncheck_interval: 30\n")
+	if err := e.load(before); err != nil {
+		t.Fatalf("파싱 실패: %v", err)
+	}
+
+	added := TunnelConfig{Name: "db", LocalPort: 5432, RemoteHost: "db", RemotePort: 5432, SSHHost: "bastion", SSHPort: 22, SSHUser: "deploy"}
+	if err := e.AddTunnel(added); err != nil {
+		t.Fatalf("AddTunnel 실패: %v", err)
+	}
+
+	want := "# 설정\ntunnels:  # 비어 있음\n  - name: \"db\"\n    local_port: 5432\n    remote_host: \"db\"\n    remote_port: 5432\n    ssh_host: \"bastion\"\n    ssh_port: 22\n    ssh_user: \"deploy\"\n    enabled: false\ncheck_interval: 30\n"
+	if got := string(e.Bytes()); got != want {
+		t.Fatalf("편집 결과가 다름\n got: %q\nwant: %q", got, want)
+	}
+}
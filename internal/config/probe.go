@@ -0,0 +1,82 @@
+package config
+
+import "time"
+
+// 상태 확인 프로브 종류
+const (
+	ProbeTCP      = "tcp"      // 터널을 통해 연결 후 배너/응답 확인
+	ProbeHTTP     = "http"     // HTTP GET 응답 코드 확인
+	ProbePostgres = "postgres" // PostgreSQL SSLRequest 응답 확인
+	ProbeMySQL    = "mysql"    // MySQL 초기 핸드셰이크 패킷 확인
+	ProbeRedis    = "redis"    // Redis PING 응답 확인
+	ProbeCommand  = "command"  // 사용자 명령 실행 (종료 코드 0이면 정상)
+)
+
+// DefaultProbeTimeout 프로브 기본 타임아웃 (초)
+const DefaultProbeTimeout = 5
+
+// ProbePortPlaceholder command 프로브에서 포워딩 로컬 포트로 바뀌는 자리 표시자
+const ProbePortPlaceholder = "{port}"
+
+// ProbeConfig 포워딩 상태 확인 프로브 설정
+//
+// 지정하지 않으면 로컬 포트 연결만 확인하며, 이 경우 ssh가 리슨만 하고 있으면
+// 원격 대상이 죽어 있어도 정상으로 보임
+type ProbeConfig struct {
+	Type    string `yaml:"type"`              // tcp, http, postgres, mysql, redis 또는 command
+	Send    string `yaml:"send,omitempty"`    // tcp 전용, 연결 후 보낼 데이터
+	Expect  string `yaml:"expect,omitempty"`  // tcp 전용, 응답에 포함되어야 하는 문자열
+	Path    string `yaml:"path,omitempty"`    // http 전용, 요청 경로 (기본값 /)
+	Status  int    `yaml:"status,omitempty"`  // http 전용, 기대하는 응답 코드 (미지정 시 400 미만이면 정상)
+	TLS     bool   `yaml:"tls,omitempty"`     // http 전용, https로 요청 (인증서 확인 안 함)
+	Command string `yaml:"command,omitempty"` // command 전용, 실행할 명령 ({port}는 로컬 포트로 바뀜)
+	Timeout int    `yaml:"timeout,omitempty"` // 타임아웃 (초, 기본값 5)
+}
+
+// Validate 프로브 설정 유효성 검사 (forwardType은 프로브를 붙인 포워딩 종류)
+func (p *ProbeConfig) Validate(forwardType string) error {
+	return firstFieldError(p.fieldErrors(forwardType))
+}
+
+// fieldErrors 프로브 설정의 모든 문제
+func (p *ProbeConfig) fieldErrors(forwardType string) []fieldError {
+	var errs []fieldError
+	switch p.Type {
+	case ProbeTCP, ProbeHTTP, ProbePostgres, ProbeMySQL, ProbeRedis:
+		// 로컬 포트로 원격 대상에 접속하는 local 포워딩에서만 의미가 있음
+		if forwardType != TypeLocal {
+			errs = append(errs, newFieldError("type", "%s 프로브는 local 포워딩에만 사용할 수 있습니다 (command 프로브 사용)", p.Type))
+		}
+	case ProbeCommand:
+		if p.Command == "" {
+			errs = append(errs, newFieldError("command", "command 프로브에는 command가 필요합니다"))
+		}
+	case "":
+		errs = append(errs, newFieldError("type", "프로브 종류가 필요합니다"))
+	default:
+		errs = append(errs, newFieldError("type", "지원하지 않는 프로브 종류: %s (tcp, http, postgres, mysql, redis 또는 command)", p.Type))
+	}
+	if p.Status != 0 && (p.Status < 100 || p.Status > 599) {
+		errs = append(errs, newFieldError("status", "유효하지 않은 HTTP 응답 코드: %d", p.Status))
+	}
+	if p.Timeout < 0 {
+		errs = append(errs, newFieldError("timeout", "유효하지 않은 프로브 timeout: %d", p.Timeout))
+	}
+	return errs
+}
+
+// GetTimeout 프로브 타임아웃 반환 (미지정 시 5초)
+func (p *ProbeConfig) GetTimeout() time.Duration {
+	if p.Timeout == 0 {
+		return DefaultProbeTimeout * time.Second
+	}
+	return time.Duration(p.Timeout) * time.Second
+}
+
+// GetPath HTTP 프로브 요청 경로 반환 (미지정 시 /)
+func (p *ProbeConfig) GetPath() string {
+	if p.Path == "" {
+		return "/"
+	}
+	return p.Path
+}
@@ -0,0 +1,137 @@
+package config
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"time"
+)
+
+// 재시도 정책 기본값
+const (
+	DefaultRetryInitialDelay = 2   // 첫 재시도 대기 시간 (초)
+	DefaultRetryMultiplier   = 2.0 // 재시도마다 대기 시간 배수
+	DefaultRetryMaxDelay     = 300 // 최대 대기 시간 (초)
+	DefaultRetryJitter       = 0.2 // 대기 시간 무작위 변동 비율 (±20%)
+	DefaultRetryMaxAttempts  = -1  // 최대 재시도 횟수 (-1: 무제한)
+)
+
+// RetryConfig 설정 파일의 자동 재연결 정책 (지정하지 않은 항목은 nil이며 상위 설정 또는 기본값 사용)
+//
+// 0도 유효한 값이므로 포인터로 "지정 안 함"과 구분:
+// jitter: 0은 무작위 변동 없음, max_delay: 0은 최대값 제한 없음, max_attempts: 0은 자동 재연결 안 함
+type RetryConfig struct {
+	InitialDelay *int     `yaml:"initial_delay,omitempty"` // 첫 재시도 대기 시간 (초, 1 이상)
+	Multiplier   *float64 `yaml:"multiplier,omitempty"`    // 재시도마다 대기 시간 배수
+	MaxDelay     *int     `yaml:"max_delay,omitempty"`     // 최대 대기 시간 (초, 0: 제한 없음)
+	Jitter       *float64 `yaml:"jitter,omitempty"`        // 대기 시간 무작위 변동 비율 (0~1)
+	MaxAttempts  *int     `yaml:"max_attempts,omitempty"`  // 최대 재시도 횟수 (-1: 무제한, 0: 재시도 안 함)
+}
+
+// RetryPolicy 터널 설정, 전역 설정, 기본값을 합친 실제 재시도 정책
+type RetryPolicy struct {
+	InitialDelay int
+	Multiplier   float64
+	MaxDelay     int
+	Jitter       float64
+	MaxAttempts  int
+}
+
+// Validate 재시도 정책 유효성 검사
+func (r *RetryConfig) Validate() error {
+	return firstFieldError(r.fieldErrors())
+}
+
+// fieldErrors 재시도 정책의 모든 문제
+func (r *RetryConfig) fieldErrors() []fieldError {
+	var errs []fieldError
+	// 0초면 실패 직후 바로 다시 시도하므로 연결이 계속 거부되는 경우 쉬지 않고 재시작하게 됨
+	if r.InitialDelay != nil && *r.InitialDelay < 1 {
+		errs = append(errs, newFieldError("initial_delay", "initial_delay는 1 이상이어야 합니다: %d", *r.InitialDelay))
+	}
+	if r.Multiplier != nil && *r.Multiplier < 1 {
+		errs = append(errs, newFieldError("multiplier", "multiplier는 1 이상이어야 합니다: %g", *r.Multiplier))
+	}
+	if r.MaxDelay != nil && *r.MaxDelay < 0 {
+		errs = append(errs, newFieldError("max_delay", "유효하지 않은 max_delay: %d", *r.MaxDelay))
+	}
+	if r.Jitter != nil && (*r.Jitter < 0 || *r.Jitter > 1) {
+		errs = append(errs, newFieldError("jitter", "jitter는 0에서 1 사이여야 합니다: %g", *r.Jitter))
+	}
+	if r.MaxAttempts != nil && *r.MaxAttempts < -1 {
+		errs = append(errs, newFieldError("max_attempts", "유효하지 않은 max_attempts: %d", *r.MaxAttempts))
+	}
+	return errs
+}
+
+// apply 지정된 항목으로 정책을 덮어쓴 결과 반환
+func (r RetryConfig) apply(policy RetryPolicy) RetryPolicy {
+	if r.InitialDelay != nil {
+		policy.InitialDelay = *r.InitialDelay
+	}
+	if r.Multiplier != nil {
+		policy.Multiplier = *r.Multiplier
+	}
+	if r.MaxDelay != nil {
+		policy.MaxDelay = *r.MaxDelay
+	}
+	if r.Jitter != nil {
+		policy.Jitter = *r.Jitter
+	}
+	if r.MaxAttempts != nil {
+		policy.MaxAttempts = *r.MaxAttempts
+	}
+	return policy
+}
+
+// defaultRetryPolicy 기본 재시도 정책
+func defaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		InitialDelay: DefaultRetryInitialDelay,
+		Multiplier:   DefaultRetryMultiplier,
+		MaxDelay:     DefaultRetryMaxDelay,
+		Jitter:       DefaultRetryJitter,
+		MaxAttempts:  DefaultRetryMaxAttempts,
+	}
+}
+
+// Unlimited 재시도 횟수 제한이 없는지 여부 (max_attempts: -1)
+func (r RetryPolicy) Unlimited() bool {
+	return r.MaxAttempts < 0
+}
+
+// Delay attempt번째 재시도 전 대기 시간 (지수 증가, ±jitter 무작위 변동, 최대값 제한)
+// max_delay를 넘지 않도록 무작위 변동을 먼저 적용한 뒤 최대값으로 자름
+func (r RetryPolicy) Delay(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	delay := float64(r.InitialDelay) * math.Pow(r.Multiplier, float64(attempt-1))
+	if r.Jitter > 0 {
+		delay *= 1 + r.Jitter*(2*rand.Float64()-1)
+	}
+	if max := float64(r.MaxDelay); max > 0 && delay > max {
+		delay = max
+	}
+	// max_delay: 0(제한 없음)이면 계속 커지므로 Duration 범위를 넘어 음수가 되지 않도록 자름
+	if limit := float64(math.MaxInt64) / float64(time.Second); delay > limit {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(delay * float64(time.Second))
+}
+
+// GetRetryPolicy 터널의 재시도 정책 반환 (터널 설정 > 전역 retry 설정 > 기본값 순)
+func (t *TunnelConfig) GetRetryPolicy() RetryPolicy {
+	policy := t.globalRetry.apply(defaultRetryPolicy())
+	if t.Retry != nil {
+		policy = t.Retry.apply(policy)
+	}
+	return policy
+}
+
+// SameConnection 재시작이 필요한 설정이 같은지 비교 (재시도 정책은 다음 재시도부터 적용되므로 비교하지 않음)
+func (t TunnelConfig) SameConnection(other TunnelConfig) bool {
+	t.Retry, other.Retry = nil, nil
+	t.globalRetry, other.globalRetry = RetryConfig{}, RetryConfig{}
+	return reflect.DeepEqual(t, other)
+}
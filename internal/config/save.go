@@ -0,0 +1,199 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigBackupDirName 설정 파일 백업 디렉토리 이름 (설정 파일과 같은 디렉토리 아래)
+const ConfigBackupDirName = "backups"
+
+// MaxConfigBackups 설정 파일마다 보관할 최대 백업 수 (오래된 것부터 삭제)
+const MaxConfigBackups = 10
+
+// configBackupTimeFormat 백업 파일 이름의 시간 형식 (이름순 정렬이 시간순이 되도록 고정 길이)
+const configBackupTimeFormat = "20060102-150405.000"
+
+// ConfigBackup 설정 파일 백업 하나
+type ConfigBackup struct {
+	Path string    `json:"path"`
+	Time time.Time `json:"time"`
+	Size int64     `json:"size"`
+}
+
+// writeConfigFile 설정 파일 내용 저장
+//
+// 기존 파일은 먼저 백업하고, 임시 파일에 다 쓴 뒤 이름을 바꿔서 교체하므로
+// 저장 중에 중단되어도 설정 파일이 잘린 채로 남지 않음
+// 비밀 값이 들어 있으면 소유자만 읽을 수 있도록 저장 (0600)
+func writeConfigFile(configPath string, data []byte) error {
+	// 심볼릭 링크면 링크 대신 실제 파일을 교체
+	target := resolveConfigPath(configPath)
+
+	perm := os.FileMode(0644)
+	if info, err := os.Stat(target); err == nil {
+		perm = info.Mode().Perm()
+		if err := backupConfigFile(target); err != nil {
+			return fmt.Errorf("설정 파일 백업 실패: %v", err)
+		}
+	}
+	if hasSecrets(data) {
+		perm &= 0600
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(target), "."+filepath.Base(target)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("설정 파일 저장 실패: %v", err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath) // 이름을 바꾼 뒤에는 아무 작업도 하지 않음
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("설정 파일 저장 실패: %v", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("설정 파일 저장 실패: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("설정 파일 저장 실패: %v", err)
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		return fmt.Errorf("설정 파일 권한 설정 실패: %v", err)
+	}
+	if err := os.Rename(tmpPath, target); err != nil {
+		return fmt.Errorf("설정 파일 저장 실패: %v", err)
+	}
+	return nil
+}
+
+// hasSecrets 설정 내용에 평문 또는 암호화된 비밀 값이 있는지 확인 (env:, file: 참조는 제외)
+// 파싱할 수 없으면 안전하게 있다고 봄
+func hasSecrets(data []byte) bool {
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return true
+	}
+
+	isSecret := func(value string) bool {
+		return value != "" && !strings.HasPrefix(value, SecretEnvPrefix) && !strings.HasPrefix(value, SecretFilePrefix)
+	}
+	if isSecret(cfg.API.Token) {
+		return true
+	}
+	for _, t := range cfg.Tunnels {
+		if isSecret(t.SSHPassword) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveConfigPath 심볼릭 링크면 실제 설정 파일 경로 반환 (백업 이름과 교체 대상은 실제 파일 기준)
+func resolveConfigPath(configPath string) string {
+	if resolved, err := filepath.EvalSymlinks(configPath); err == nil {
+		return resolved
+	}
+	return configPath
+}
+
+// ConfigBackupDir 설정 파일 백업 디렉토리 경로 반환
+func ConfigBackupDir(configPath string) string {
+	return filepath.Join(filepath.Dir(resolveConfigPath(configPath)), ConfigBackupDirName)
+}
+
+// backupConfigFile 현재 설정 파일을 시간이 붙은 이름으로 백업하고 오래된 백업 삭제
+// 가장 최근 백업과 내용이 같으면 새로 만들지 않음
+func backupConfigFile(configPath string) error {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return err
+	}
+
+	backups, err := ListConfigBackups(configPath)
+	if err != nil {
+		return err
+	}
+	if len(backups) > 0 {
+		if latest, err := os.ReadFile(backups[0].Path); err == nil && bytes.Equal(latest, data) {
+			return nil
+		}
+	}
+
+	dir := ConfigBackupDir(configPath)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	// 비밀 값이 들어 있을 수 있으므로 백업은 항상 소유자만 읽을 수 있게 저장
+	name := fmt.Sprintf("%s.%s.bak", filepath.Base(resolveConfigPath(configPath)), time.Now().Format(configBackupTimeFormat))
+	if err := os.WriteFile(filepath.Join(dir, name), data, 0600); err != nil {
+		return err
+	}
+
+	backups, err = ListConfigBackups(configPath)
+	if err != nil {
+		return err
+	}
+	for _, backup := range backups[min(len(backups), MaxConfigBackups):] {
+		os.Remove(backup.Path)
+	}
+	return nil
+}
+
+// ListConfigBackups 설정 파일의 백업 목록 반환 (최근 것부터)
+func ListConfigBackups(configPath string) ([]ConfigBackup, error) {
+	dir := ConfigBackupDir(configPath)
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("백업 디렉토리 읽기 실패: %v", err)
+	}
+
+	prefix := filepath.Base(resolveConfigPath(configPath)) + "."
+	var backups []ConfigBackup
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ".bak") {
+			continue
+		}
+		stamp := strings.TrimSuffix(strings.TrimPrefix(name, prefix), ".bak")
+		t, err := time.ParseInLocation(configBackupTimeFormat, stamp, time.Local)
+		if err != nil {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		backups = append(backups, ConfigBackup{Path: filepath.Join(dir, name), Time: t, Size: info.Size()})
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].Time.After(backups[j].Time)
+	})
+	return backups, nil
+}
+
+// RestoreConfigBackup 백업 파일로 설정 파일 복원 (현재 설정 파일도 먼저 백업되므로 되돌릴 수 있음)
+func RestoreConfigBackup(configPath, backupPath string) error {
+	data, err := os.ReadFile(backupPath)
+	if err != nil {
+		return fmt.Errorf("백업 파일 읽기 실패: %v", err)
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return fmt.Errorf("백업 파일 파싱 실패: %v", err)
+	}
+	return writeConfigFile(configPath, data)
+}
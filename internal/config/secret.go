@@ -0,0 +1,176 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// 비밀 값 참조 접두사 (ssh_password 등에 평문 대신 사용)
+const (
+	SecretEnvPrefix  = "env:"  // 환경 변수 (env:VAR)
+	SecretFilePrefix = "file:" // 파일 내용 (file:path, 상대 경로는 설정 파일 기준)
+	SecretEncPrefix  = "enc:"  // 마스터 키로 암호화된 값 (tunnels encrypt로 생성)
+)
+
+// MasterKeyFileName 설정 파일 옆에 두는 암호화 마스터 키 파일 이름
+const MasterKeyFileName = "tunnels.key"
+
+// masterKeySize AES-256 키 길이
+const masterKeySize = 32
+
+// MasterKeyPath 설정 파일과 같은 디렉토리의 마스터 키 경로 반환
+func MasterKeyPath(configPath string) string {
+	return filepath.Join(filepath.Dir(configPath), MasterKeyFileName)
+}
+
+// IsSecretRef 비밀 값 참조 형식인지 확인
+func IsSecretRef(value string) bool {
+	return strings.HasPrefix(value, SecretEnvPrefix) ||
+		strings.HasPrefix(value, SecretFilePrefix) ||
+		strings.HasPrefix(value, SecretEncPrefix)
+}
+
+// ResolveSecret 비밀 값 참조를 실제 값으로 변환 (참조 형식이 아니면 그대로 반환)
+func ResolveSecret(value, configPath string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, SecretEnvPrefix):
+		name := strings.TrimPrefix(value, SecretEnvPrefix)
+		secret, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("환경 변수 %s가 설정되지 않았습니다", name)
+		}
+		return secret, nil
+
+	case strings.HasPrefix(value, SecretFilePrefix):
+		path := strings.TrimPrefix(value, SecretFilePrefix)
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(filepath.Dir(configPath), path)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("비밀 값 파일 읽기 실패: %v", err)
+		}
+		// 편집기가 붙이는 마지막 줄바꿈은 값에 포함하지 않음
+		return strings.TrimRight(string(data), "\r\n"), nil
+
+	case strings.HasPrefix(value, SecretEncPrefix):
+		keyPath := MasterKeyPath(configPath)
+		key, err := loadMasterKey(keyPath)
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("마스터 키 파일이 없습니다: %s", keyPath)
+		}
+		if err != nil {
+			return "", err
+		}
+		return decryptSecret(strings.TrimPrefix(value, SecretEncPrefix), key)
+
+	default:
+		return value, nil
+	}
+}
+
+// EncryptSecret 마스터 키로 값을 암호화해서 설정 파일에 넣을 "enc:..." 문자열 반환
+// (마스터 키 파일이 없으면 새로 생성)
+func EncryptSecret(plaintext, configPath string) (string, error) {
+	keyPath := MasterKeyPath(configPath)
+	key, err := loadMasterKey(keyPath)
+	if os.IsNotExist(err) {
+		key, err = createMasterKey(keyPath)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("암호화 nonce 생성 실패: %v", err)
+	}
+
+	// nonce + 암호문을 base64로 인코딩
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+	return SecretEncPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// decryptSecret "enc:" 뒤의 base64 값을 복호화
+func decryptSecret(encoded string, key []byte) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("암호화된 값 형식 오류: %v", err)
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return "", fmt.Errorf("암호화된 값이 너무 짧습니다")
+	}
+
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("복호화 실패 (마스터 키가 다르거나 값이 손상됨)")
+	}
+	return string(plaintext), nil
+}
+
+// newGCM AES-256-GCM 생성
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("암호화 초기화 실패: %v", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("암호화 초기화 실패: %v", err)
+	}
+	return gcm, nil
+}
+
+// loadMasterKey 마스터 키 파일 읽기 (hex 인코딩된 32바이트)
+func loadMasterKey(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("마스터 키 파일 읽기 실패: %v", err)
+	}
+
+	key, err := hex.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(key) != masterKeySize {
+		return nil, fmt.Errorf("마스터 키 파일 형식 오류: %s", path)
+	}
+	return key, nil
+}
+
+// createMasterKey 새 마스터 키 생성 후 파일로 저장 (소유자만 읽기/쓰기)
+func createMasterKey(path string) ([]byte, error) {
+	key := make([]byte, masterKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("마스터 키 생성 실패: %v", err)
+	}
+
+	// 이미 있는 키를 덮어쓰지 않도록 O_EXCL 사용
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("마스터 키 파일 생성 실패: %v", err)
+	}
+	defer f.Close()
+
+	if _, err := f.WriteString(hex.EncodeToString(key) + "\n"); err != nil {
+		return nil, fmt.Errorf("마스터 키 파일 저장 실패: %v", err)
+	}
+	return key, nil
+}
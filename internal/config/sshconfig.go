@@ -0,0 +1,667 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// sshConfigMaxDepth Include 중첩 최대 깊이 (OpenSSH와 같은 값)
+const sshConfigMaxDepth = 16
+
+// ErrNoSSHForwards 포워딩 설정(LocalForward, RemoteForward, DynamicForward)이 없는 Host
+var ErrNoSSHForwards = errors.New("포워딩 설정(LocalForward, RemoteForward, DynamicForward)이 없습니다")
+
+// SSHConfig 파싱한 OpenSSH 클라이언트 설정 (~/.ssh/config)
+//
+// Host 블록과 Include를 지원하고, Match 블록은 조건을 평가하지 않고 건너뜀
+// (Host 블록 밖에 있는 설정은 모든 Host에 적용)
+type SSHConfig struct {
+	lines []sshConfigLine
+}
+
+// sshConfigLine ssh_config의 설정 한 줄 (Include는 포함된 파일들의 줄을 included에 담음)
+type sshConfigLine struct {
+	keyword  string // 소문자
+	args     []string
+	file     string
+	line     int
+	included []sshConfigLine
+}
+
+// SSHHost ssh_config에서 Host 별칭 하나에 적용되는 설정 (OpenSSH처럼 처음 나온 값을 사용)
+type SSHHost struct {
+	Alias           string
+	HostName        string // 미지정 시 별칭
+	User            string
+	Port            int // 미지정 시 22
+	IdentityFiles   []string
+	ProxyJump       string
+	LocalForwards   [][]string // LocalForward 인자 ([bind_address:]port host:hostport)
+	RemoteForwards  [][]string // RemoteForward 인자 ([bind_address:]port host:hostport)
+	DynamicForwards [][]string // DynamicForward 인자 ([bind_address:]port)
+
+	warnings []string // 지원하지 않거나 잘못된 설정 (파일:줄 포함)
+}
+
+// DefaultSSHConfigPath 사용자 OpenSSH 설정 파일 경로 (~/.ssh/config)
+func DefaultSSHConfigPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".ssh", "config")
+	}
+	return filepath.Join(home, ".ssh", "config")
+}
+
+// SystemSSHConfigPath 시스템 OpenSSH 설정 파일 경로 (Windows는 %ProgramData%\ssh\ssh_config)
+func SystemSSHConfigPath() string {
+	if runtime.GOOS == "windows" {
+		return filepath.Join(os.Getenv("ProgramData"), "ssh", "ssh_config")
+	}
+	return "/etc/ssh/ssh_config"
+}
+
+// LoadSSHConfig ssh_config 파일 파싱 (Include된 파일 포함)
+// Include의 상대 경로는 OpenSSH처럼 사용자 설정 디렉토리(설정 파일이 있는 디렉토리) 기준
+func LoadSSHConfig(path string) (*SSHConfig, error) {
+	lines, err := parseSSHConfigFile(path, filepath.Dir(path), 0)
+	if err != nil {
+		return nil, err
+	}
+	return &SSHConfig{lines: lines}, nil
+}
+
+// loadSSHConfigs OpenSSH가 읽는 사용자, 시스템 설정 파일 파싱 (없는 파일은 건너뜀)
+func loadSSHConfigs() ([]*SSHConfig, error) {
+	var configs []*SSHConfig
+	for _, path := range []string{DefaultSSHConfigPath(), SystemSSHConfigPath()} {
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		sshConfig, err := LoadSSHConfig(path)
+		if err != nil {
+			return nil, err
+		}
+		configs = append(configs, sshConfig)
+	}
+	return configs, nil
+}
+
+// parseSSHConfigFile ssh_config 파일 하나를 줄 단위로 파싱
+func parseSSHConfigFile(path, baseDir string, depth int) ([]sshConfigLine, error) {
+	if depth > sshConfigMaxDepth {
+		return nil, fmt.Errorf("%s: Include 중첩이 너무 깊습니다", path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("ssh 설정 파일 읽기 실패: %v", err)
+	}
+
+	var lines []sshConfigLine
+	for i, text := range strings.Split(string(data), "\n") {
+		keyword, args, err := splitSSHConfigLine(text)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", path, i+1, err)
+		}
+		if keyword == "" {
+			continue
+		}
+
+		line := sshConfigLine{keyword: keyword, args: args, file: path, line: i + 1}
+		if keyword == "include" {
+			for _, pattern := range args {
+				matches, err := filepath.Glob(sshIncludePath(pattern, baseDir))
+				if err != nil {
+					return nil, fmt.Errorf("%s:%d: 잘못된 Include 경로: %s", path, i+1, pattern)
+				}
+				// 일치하는 파일이 없으면 OpenSSH처럼 무시
+				for _, match := range matches {
+					if info, err := os.Stat(match); err != nil || info.IsDir() {
+						continue
+					}
+					included, err := parseSSHConfigFile(match, baseDir, depth+1)
+					if err != nil {
+						return nil, err
+					}
+					line.included = append(line.included, included...)
+				}
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines, nil
+}
+
+// sshIncludePath Include 경로의 ~를 홈 디렉토리로 바꾸고 상대 경로는 baseDir 기준으로 변환
+func sshIncludePath(pattern, baseDir string) string {
+	pattern = expandHome(pattern)
+	if !filepath.IsAbs(pattern) {
+		pattern = filepath.Join(baseDir, pattern)
+	}
+	return pattern
+}
+
+// expandHome 경로 앞의 ~를 홈 디렉토리로 변환
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") && !strings.HasPrefix(path, `~\`) {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[1:])
+}
+
+// splitSSHConfigLine ssh_config 한 줄을 키워드(소문자)와 인자로 분리 (빈 줄, 주석은 빈 키워드)
+// "Keyword value"와 "Keyword=value" 형식, 큰따옴표로 묶은 인자, 줄 끝 주석 지원
+func splitSSHConfigLine(text string) (string, []string, error) {
+	text = strings.TrimSpace(text)
+	if text == "" || text[0] == '#' {
+		return "", nil, nil
+	}
+
+	end := strings.IndexAny(text, " \t=")
+	if end < 0 {
+		return "", nil, fmt.Errorf("%s 값이 없습니다", text)
+	}
+	keyword := strings.ToLower(text[:end])
+	rest := strings.TrimLeft(text[end:], " \t")
+	if strings.HasPrefix(rest, "=") {
+		rest = strings.TrimLeft(rest[1:], " \t")
+	}
+
+	var args []string
+	var arg strings.Builder
+	inArg, quoted := false, false
+scan:
+	for _, r := range rest {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			inArg = true
+		case quoted:
+			arg.WriteRune(r)
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		case r == '#' && !inArg:
+			// 인자 시작 위치의 #부터는 주석
+			break scan
+		default:
+			arg.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quoted {
+		return "", nil, fmt.Errorf("닫히지 않은 따옴표가 있습니다")
+	}
+	if inArg {
+		args = append(args, arg.String())
+	}
+	if len(args) == 0 {
+		return "", nil, fmt.Errorf("%s 값이 없습니다", text[:end])
+	}
+	return keyword, args, nil
+}
+
+// Hosts 와일드카드나 !가 없는 Host 별칭 목록 (설정 파일 순서, Include된 파일 포함)
+func (s *SSHConfig) Hosts() []string {
+	var hosts []string
+	seen := make(map[string]bool)
+	var walk func(lines []sshConfigLine)
+	walk = func(lines []sshConfigLine) {
+		for _, line := range lines {
+			switch line.keyword {
+			case "host":
+				for _, pattern := range line.args {
+					if !strings.ContainsAny(pattern, "*?!") && !seen[pattern] {
+						seen[pattern] = true
+						hosts = append(hosts, pattern)
+					}
+				}
+			case "include":
+				walk(line.included)
+			}
+		}
+	}
+	walk(s.lines)
+	return hosts
+}
+
+// HasHost 별칭과 일치하는 Host 블록이 있는지 확인 (모든 호스트에 적용되는 Host * 블록은 제외)
+func (s *SSHConfig) HasHost(alias string) bool {
+	var walk func(lines []sshConfigLine) bool
+	walk = func(lines []sshConfigLine) bool {
+		for _, line := range lines {
+			switch line.keyword {
+			case "host":
+				if !slices.Contains(line.args, "*") && matchSSHHost(line.args, alias) {
+					return true
+				}
+			case "include":
+				if walk(line.included) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	return walk(s.lines)
+}
+
+// Resolve Host 별칭에 적용되는 설정 계산 (ssh -G처럼 처음 일치한 값을 사용)
+func (s *SSHConfig) Resolve(alias string) SSHHost {
+	h := SSHHost{Alias: alias}
+	active := true
+	h.apply(s.lines, &active)
+
+	if h.HostName == "" {
+		h.HostName = alias
+	}
+	h.HostName = strings.NewReplacer("%%", "%", "%h", alias).Replace(h.HostName)
+	if h.Port == 0 {
+		h.Port = 22
+	}
+	return h
+}
+
+// apply 적용 대상인 줄의 설정을 반영 (active는 현재 Host 블록이 별칭과 일치하는지 여부)
+func (h *SSHHost) apply(lines []sshConfigLine, active *bool) {
+	for _, line := range lines {
+		switch line.keyword {
+		case "host":
+			*active = matchSSHHost(line.args, h.Alias)
+			continue
+		case "match":
+			// Match 조건은 평가하지 않으므로 다음 Host 블록까지 적용하지 않음
+			*active = false
+			continue
+		}
+		if !*active {
+			continue
+		}
+
+		switch line.keyword {
+		case "include":
+			// 포함된 파일의 Host 블록은 Include가 있는 블록 안에서만 유효
+			saved := *active
+			h.apply(line.included, active)
+			*active = saved
+		case "hostname":
+			if h.HostName == "" {
+				h.HostName = line.args[0]
+			}
+		case "user":
+			if h.User == "" {
+				h.User = line.args[0]
+			}
+		case "port":
+			if h.Port == 0 {
+				port, err := strconv.Atoi(line.args[0])
+				if err != nil || port <= 0 || port > 65535 {
+					h.warn(line, "잘못된 Port 값: %s", line.args[0])
+					continue
+				}
+				h.Port = port
+			}
+		case "identityfile":
+			h.IdentityFiles = append(h.IdentityFiles, line.args[0])
+		case "proxyjump":
+			if h.ProxyJump == "" {
+				h.ProxyJump = line.args[0]
+			}
+		case "proxycommand":
+			if !strings.EqualFold(line.args[0], "none") {
+				h.warn(line, "ProxyCommand는 지원하지 않습니다 (ProxyJump 사용)")
+			}
+		case "localforward":
+			h.LocalForwards = append(h.LocalForwards, line.args)
+		case "remoteforward":
+			h.RemoteForwards = append(h.RemoteForwards, line.args)
+		case "dynamicforward":
+			h.DynamicForwards = append(h.DynamicForwards, line.args)
+		}
+	}
+}
+
+// warn 설정 파일 위치와 함께 경고 기록
+func (h *SSHHost) warn(line sshConfigLine, format string, args ...interface{}) {
+	h.warnings = append(h.warnings, fmt.Sprintf("%s:%d: %s", line.file, line.line, fmt.Sprintf(format, args...)))
+}
+
+// matchSSHHost Host 패턴 목록과 별칭 비교 (대소문자 무시, !로 시작하는 패턴과 일치하면 제외)
+func matchSSHHost(patterns []string, host string) bool {
+	host = strings.ToLower(host)
+	matched := false
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(pattern)
+		if strings.HasPrefix(pattern, "!") {
+			if matchWildcard(pattern[1:], host) {
+				return false
+			}
+			continue
+		}
+		if matchWildcard(pattern, host) {
+			matched = true
+		}
+	}
+	return matched
+}
+
+// matchWildcard ssh_config 와일드카드 비교 (*는 0개 이상의 문자, ?는 한 문자)
+func matchWildcard(pattern, s string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '*':
+			for i := 0; i <= len(s); i++ {
+				if matchWildcard(pattern[1:], s[i:]) {
+					return true
+				}
+			}
+			return false
+		case '?':
+			if s == "" {
+				return false
+			}
+		default:
+			if s == "" || s[0] != pattern[0] {
+				return false
+			}
+		}
+		pattern, s = pattern[1:], s[1:]
+	}
+	return s == ""
+}
+
+// ImportTunnel Host 별칭 하나를 터널 설정으로 변환 (터널 이름은 별칭, 비활성화 상태)
+//
+// User가 없으면 현재 사용자, IdentityFile이 없으면 SSH 에이전트 인증을 사용하고
+// ProxyJump의 각 호스트도 ssh_config에서 찾아 점프 호스트로 변환함
+// 변환할 수 없는 설정은 건너뛰고 경고 목록으로 반환하며, 포워딩이 하나도 없으면 ErrNoSSHForwards
+func (s *SSHConfig) ImportTunnel(alias string) (TunnelConfig, []string, error) {
+	h := s.Resolve(alias)
+	forwards, warnings, err := importForwards(h)
+	warnings = append(h.warnings, warnings...)
+	if err != nil {
+		return TunnelConfig{}, warnings, err
+	}
+
+	t := TunnelConfig{
+		Name:    alias,
+		SSHHost: h.HostName,
+		SSHPort: h.Port,
+		SSHUser: h.User,
+	}
+	if t.SSHUser == "" {
+		t.SSHUser = localUserName()
+	}
+	if len(h.IdentityFiles) > 0 {
+		t.SSHKeyPath = expandSSHTokens(h.IdentityFiles[0], h.HostName, t.SSHUser)
+		if len(h.IdentityFiles) > 1 {
+			warnings = append(warnings, fmt.Sprintf("IdentityFile이 여러 개이면 첫 번째 키만 사용합니다: %s", t.SSHKeyPath))
+		}
+	} else {
+		t.UseAgent = true
+	}
+
+	if h.ProxyJump != "" && !strings.EqualFold(h.ProxyJump, "none") {
+		for _, hop := range strings.Split(h.ProxyJump, ",") {
+			jump, err := s.importJumpHost(strings.TrimSpace(hop), t)
+			if err != nil {
+				return TunnelConfig{}, warnings, fmt.Errorf("ProxyJump %s: %v", hop, err)
+			}
+			t.JumpHosts = append(t.JumpHosts, jump)
+		}
+	}
+
+	t.setForwards(forwards)
+	return t, warnings, nil
+}
+
+// ImportAliasTunnel Host 별칭을 ssh_alias로 참조하는 터널 설정으로 변환 (터널 이름은 별칭, 비활성화 상태)
+//
+// 접속 정보(HostName, User, Port, IdentityFile, ProxyJump 등)는 복사하지 않고 실행할 때 OpenSSH가 해석하며,
+// 상태 확인에 필요한 포워딩만 가져옴
+func (s *SSHConfig) ImportAliasTunnel(alias string) (TunnelConfig, []string, error) {
+	forwards, warnings, err := importForwards(s.Resolve(alias))
+	if err != nil {
+		return TunnelConfig{}, warnings, err
+	}
+	t := TunnelConfig{Name: alias, SSHAlias: alias}
+	t.setForwards(forwards)
+	return t, warnings, nil
+}
+
+// importForwards Host의 LocalForward/RemoteForward/DynamicForward를 포워딩 설정으로 변환
+// 변환할 수 없는 포워딩은 건너뛰고 경고 목록으로 반환
+func importForwards(h SSHHost) ([]ForwardConfig, []string, error) {
+	var forwards []ForwardConfig
+	var warnings []string
+	for _, group := range []struct {
+		keyword     string
+		forwardType string
+		specs       [][]string
+	}{
+		{"LocalForward", TypeLocal, h.LocalForwards},
+		{"RemoteForward", TypeRemote, h.RemoteForwards},
+		{"DynamicForward", TypeDynamic, h.DynamicForwards},
+	} {
+		for _, spec := range group.specs {
+			f, warning, err := parseSSHForward(group.forwardType, spec)
+			warnings = appendForwardWarning(warnings, group.keyword, spec, warning, err)
+			if err == nil {
+				forwards = append(forwards, f)
+			}
+		}
+	}
+	if len(forwards) == 0 {
+		if len(h.LocalForwards)+len(h.RemoteForwards)+len(h.DynamicForwards) > 0 {
+			return nil, warnings, fmt.Errorf("변환할 수 있는 포워딩이 없습니다")
+		}
+		return nil, warnings, ErrNoSSHForwards
+	}
+	return forwards, warnings, nil
+}
+
+// setForwards 포워딩이 하나면 터널의 단일 포워딩 필드에, 여러 개면 forwards 목록에 설정
+func (t *TunnelConfig) setForwards(forwards []ForwardConfig) {
+	if len(forwards) != 1 {
+		t.Forwards = forwards
+		return
+	}
+	f := forwards[0]
+	t.Type = f.Type
+	t.LocalHost = f.LocalHost
+	t.LocalPort = f.LocalPort
+	t.RemoteHost = f.RemoteHost
+	t.RemoteBindAddress = f.RemoteBindAddress
+	t.RemotePort = f.RemotePort
+}
+
+// importJumpHost ProxyJump 호스트 하나([user@]host[:port])를 점프 호스트 설정으로 변환
+// 호스트가 ssh_config의 별칭이면 HostName/User/Port/IdentityFile을 따르고, 터널과 같은 값은 생략
+func (s *SSHConfig) importJumpHost(spec string, t TunnelConfig) (JumpHostConfig, error) {
+	spec = strings.TrimPrefix(spec, "ssh://")
+	userName := ""
+	if i := strings.LastIndex(spec, "@"); i >= 0 {
+		userName, spec = spec[:i], spec[i+1:]
+	}
+	host, port := spec, 0
+	if h, p, err := net.SplitHostPort(spec); err == nil {
+		n, err := strconv.Atoi(p)
+		if err != nil || n <= 0 || n > 65535 {
+			return JumpHostConfig{}, fmt.Errorf("잘못된 포트: %s", p)
+		}
+		host, port = h, n
+	}
+	if host == "" {
+		return JumpHostConfig{}, fmt.Errorf("호스트가 필요합니다")
+	}
+
+	resolved := s.Resolve(host)
+	if userName == "" {
+		userName = resolved.User
+	}
+	if port == 0 {
+		port = resolved.Port
+	}
+
+	jump := JumpHostConfig{Host: resolved.HostName}
+	if port != 22 {
+		jump.Port = port
+	}
+	if userName != "" && userName != t.SSHUser {
+		jump.User = userName
+	}
+	if len(resolved.IdentityFiles) > 0 {
+		keyPath := expandSSHTokens(resolved.IdentityFiles[0], resolved.HostName, jump.GetUser(t.SSHUser))
+		if keyPath != t.SSHKeyPath {
+			jump.KeyPath = keyPath
+		}
+	}
+	return jump, nil
+}
+
+// parseSSHForward LocalForward/RemoteForward/DynamicForward 인자를 포워딩 설정으로 변환
+// 변환은 되지만 의미가 달라지는 설정은 warning으로 반환
+func parseSSHForward(forwardType string, spec []string) (ForwardConfig, string, error) {
+	f := ForwardConfig{}
+	if forwardType != TypeLocal {
+		f.Type = forwardType
+	}
+
+	want := 2
+	if forwardType == TypeDynamic {
+		want = 1
+	}
+	if len(spec) != want {
+		if forwardType == TypeRemote && len(spec) == 1 {
+			return f, "", fmt.Errorf("원격 동적 포워딩(SOCKS)은 지원하지 않습니다")
+		}
+		return f, "", fmt.Errorf("인자 개수가 올바르지 않습니다")
+	}
+
+	bind, port, err := parseForwardListen(spec[0])
+	if err != nil {
+		return f, "", err
+	}
+	warning := ""
+	if forwardType == TypeRemote {
+		f.RemoteBindAddress = bind
+		f.RemotePort = port
+	} else {
+		f.LocalPort = port
+		// 로컬 포워딩과 SOCKS5 프록시는 항상 루프백 주소에만 리슨
+		switch bind {
+		case "", "localhost", "127.0.0.1", "::1":
+		default:
+			warning = fmt.Sprintf("바인드 주소 %s 대신 루프백 주소에 리슨합니다", bind)
+		}
+	}
+
+	if forwardType != TypeDynamic {
+		host, port, err := parseForwardTarget(spec[1])
+		if err != nil {
+			return f, "", err
+		}
+		if forwardType == TypeRemote {
+			if host != DefaultLocalHost {
+				f.LocalHost = host
+			}
+			f.LocalPort = port
+		} else {
+			f.RemoteHost = host
+			f.RemotePort = port
+		}
+	}
+	return f, warning, nil
+}
+
+// parseForwardListen [bind_address:]port 형식 파싱 (IPv6 주소는 [::1]:port)
+func parseForwardListen(spec string) (string, int, error) {
+	if strings.Contains(spec, "/") {
+		return "", 0, fmt.Errorf("Unix 소켓 포워딩은 지원하지 않습니다")
+	}
+	if !strings.Contains(spec, ":") {
+		port, err := parseForwardPort(spec)
+		return "", port, err
+	}
+	host, p, err := net.SplitHostPort(spec)
+	if err != nil {
+		return "", 0, fmt.Errorf("잘못된 주소: %s", spec)
+	}
+	port, err := parseForwardPort(p)
+	return host, port, err
+}
+
+// parseForwardTarget host:hostport 형식 파싱 (IPv6 주소는 [::1]:port)
+func parseForwardTarget(spec string) (string, int, error) {
+	if strings.HasPrefix(spec, "/") {
+		return "", 0, fmt.Errorf("Unix 소켓 포워딩은 지원하지 않습니다")
+	}
+	host, p, err := net.SplitHostPort(spec)
+	if err != nil || host == "" {
+		return "", 0, fmt.Errorf("잘못된 대상 주소: %s", spec)
+	}
+	port, err := parseForwardPort(p)
+	return host, port, err
+}
+
+// parseForwardPort 포트 번호 파싱 (1~65535)
+func parseForwardPort(s string) (int, error) {
+	port, err := strconv.Atoi(s)
+	if err != nil || port <= 0 || port > 65535 {
+		return 0, fmt.Errorf("잘못된 포트: %s", s)
+	}
+	return port, nil
+}
+
+// appendForwardWarning 포워딩 변환 결과의 경고나 오류를 경고 목록에 추가
+func appendForwardWarning(warnings []string, keyword string, spec []string, warning string, err error) []string {
+	switch {
+	case err != nil:
+		return append(warnings, fmt.Sprintf("%s %s 건너뜀: %v", keyword, strings.Join(spec, " "), err))
+	case warning != "":
+		return append(warnings, fmt.Sprintf("%s %s: %s", keyword, strings.Join(spec, " "), warning))
+	}
+	return warnings
+}
+
+// expandSSHTokens IdentityFile 경로의 ~와 %d(홈), %u(로컬 사용자), %h(호스트), %r(원격 사용자), %% 변환
+func expandSSHTokens(path, host, remoteUser string) string {
+	home, _ := os.UserHomeDir()
+	path = strings.NewReplacer(
+		"%%", "%",
+		"%d", home,
+		"%u", localUserName(),
+		"%h", host,
+		"%r", remoteUser,
+	).Replace(path)
+	return expandHome(path)
+}
+
+// localUserName 현재 로컬 사용자 이름 (Windows의 DOMAIN\ 접두사 제외)
+func localUserName() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		name := u.Username
+		if i := strings.LastIndex(name, `\`); i >= 0 {
+			name = name[i+1:]
+		}
+		return name
+	}
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	return os.Getenv("USERNAME")
+}
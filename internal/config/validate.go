@@ -0,0 +1,422 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ValidationError 설정 파일 검사에서 찾은 문제 하나 (Line, Column은 1부터, 위치를 모르면 0)
+type ValidationError struct {
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+	Tunnel  string `json:"tunnel,omitempty"` // 문제가 있는 터널 이름 (설정 파일 전체 문제면 빈 값)
+	Message string `json:"message"`
+}
+
+// Error 줄:열: 터널 'name': 메시지 형식
+func (e ValidationError) Error() string {
+	var b strings.Builder
+	if e.Line > 0 {
+		fmt.Fprintf(&b, "%d:%d: ", e.Line, e.Column)
+	}
+	if e.Tunnel != "" {
+		fmt.Fprintf(&b, "터널 '%s': ", e.Tunnel)
+	}
+	b.WriteString(e.Message)
+	return b.String()
+}
+
+// ValidationErrors Config.Validate가 찾은 모든 문제 (설정 파일 줄 순서)
+type ValidationErrors []ValidationError
+
+// Error 문제마다 한 줄씩
+func (e ValidationErrors) Error() string {
+	lines := make([]string, len(e))
+	for i, err := range e {
+		lines[i] = err.Error()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Validate 설정 파일 전체 검사
+//
+// 터널별 설정 오류, 중복된 터널 이름, 겹치는 로컬 포트, 알 수 없는 키,
+// 활성화된 터널의 키 파일 문제와 ssh_config에 없는 ssh_alias를 한 번에 모아서 ValidationErrors로 반환 (문제가 없으면 nil)
+// LoadConfig로 읽은 설정이면 각 문제에 설정 파일의 줄/열 위치가 붙음
+func (c *Config) Validate() error {
+	v := &validator{config: c}
+	v.locate()
+
+	v.checkUnknownKeys()
+	if c.API.Enabled {
+		if err := c.API.Validate(); err != nil {
+			v.add(v.apiNode("listen"), "", "api: %v", err)
+		}
+	}
+	for _, e := range c.Retry.fieldErrors() {
+		v.add(walkPath(v.root, append([]string{"retry"}, e.path...)), "", "retry: %v", e.err)
+	}
+	for i := range c.Tunnels {
+		t := &c.Tunnels[i]
+		for _, e := range t.fieldErrors() {
+			v.add(walkPath(v.tunnelNode(i), e.path), t.Name, "%v", e.err)
+		}
+	}
+	v.checkDuplicateNames()
+	v.checkLocalPorts()
+	v.checkKeyFiles()
+	v.checkSSHAliases()
+
+	if len(v.errs) == 0 {
+		return nil
+	}
+	sort.SliceStable(v.errs, func(i, j int) bool {
+		a, b := v.errs[i], v.errs[j]
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return v.errs
+}
+
+// fieldError 설정 항목 하나의 문제와 설정 파일 안의 위치 (Config.Validate에서 모든 문제를 줄/열과 함께 보고할 때 사용)
+type fieldError struct {
+	path []string // 항목 매핑에서 문제가 있는 값까지의 키 (목록 항목은 "0"부터의 순서, 비어 있으면 항목 전체)
+	err  error
+}
+
+// newFieldError key 값의 문제 (key가 빈 값이면 항목 전체 문제)
+func newFieldError(key string, format string, args ...interface{}) fieldError {
+	var path []string
+	if key != "" {
+		path = []string{key}
+	}
+	return fieldError{path: path, err: fmt.Errorf(format, args...)}
+}
+
+// nestFieldErrors 하위 항목의 문제를 상위 항목 기준으로 변환 (위치 앞에 path, 메시지 앞에 label을 붙임)
+func nestFieldErrors(errs []fieldError, label string, path ...string) []fieldError {
+	nested := make([]fieldError, len(errs))
+	for i, e := range errs {
+		nested[i].path = append(append([]string(nil), path...), e.path...)
+		nested[i].err = e.err
+		if label != "" {
+			nested[i].err = fmt.Errorf("%s: %v", label, e.err)
+		}
+	}
+	return nested
+}
+
+// firstFieldError 첫 번째 문제 (없으면 nil)
+func firstFieldError(errs []fieldError) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs[0].err
+}
+
+// validator Config.Validate 진행 상태 (찾은 문제와 설정 파일 노드 위치)
+type validator struct {
+	config  *Config
+	root    *yaml.Node   // 설정 파일 최상위 매핑 (위치를 모르면 nil)
+	tunnels []*yaml.Node // Config.Tunnels와 같은 순서의 터널 매핑 노드
+	errs    ValidationErrors
+}
+
+// locate LoadConfig에서 보관한 문서 노드에서 최상위 매핑과 터널 노드 찾기
+func (v *validator) locate() {
+	doc := v.config.doc
+	if doc == nil || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return
+	}
+	v.root = doc.Content[0]
+	_, seq := mappingValue(v.root, "tunnels")
+	// 노드와 설정 순서가 어긋나면 위치를 잘못 알려주지 않도록 터널 위치는 쓰지 않음
+	if seq != nil && seq.Kind == yaml.SequenceNode && len(seq.Content) == len(v.config.Tunnels) {
+		v.tunnels = seq.Content
+	}
+}
+
+// add 노드 위치와 함께 문제 기록 (node가 nil이면 위치 없이)
+func (v *validator) add(node *yaml.Node, tunnel string, format string, args ...interface{}) {
+	err := ValidationError{Tunnel: tunnel, Message: fmt.Sprintf(format, args...)}
+	if node != nil {
+		err.Line, err.Column = node.Line, node.Column
+	}
+	v.errs = append(v.errs, err)
+}
+
+// tunnelNode i번째 터널의 매핑 노드
+func (v *validator) tunnelNode(i int) *yaml.Node {
+	if i >= len(v.tunnels) {
+		return nil
+	}
+	return v.tunnels[i]
+}
+
+// tunnelField i번째 터널의 key 값 노드 (키가 없으면 터널 노드)
+func (v *validator) tunnelField(i int, key string) *yaml.Node {
+	node := v.tunnelNode(i)
+	return fieldNode(node, key)
+}
+
+// forwardField i번째 터널의 j번째 포워딩의 key 값 노드
+// 단일 포워딩 터널은 터널 자체의 필드를 사용
+func (v *validator) forwardField(i, j int, key string) *yaml.Node {
+	if len(v.config.Tunnels[i].Forwards) == 0 {
+		return v.tunnelField(i, key)
+	}
+	return fieldNode(itemNode(v.tunnelField(i, "forwards"), j), key)
+}
+
+// jumpHostField i번째 터널의 j번째 점프 호스트의 key 값 노드
+func (v *validator) jumpHostField(i, j int, key string) *yaml.Node {
+	return fieldNode(itemNode(v.tunnelField(i, "jump_hosts"), j), key)
+}
+
+// apiNode api 설정의 key 값 노드 (키가 없으면 api 노드)
+func (v *validator) apiNode(key string) *yaml.Node {
+	if v.root == nil {
+		return nil
+	}
+	_, api := mappingValue(v.root, "api")
+	return fieldNode(api, key)
+}
+
+// tunnelAt 줄 번호가 속한 터널 이름 (터널 밖이면 빈 값)
+func (v *validator) tunnelAt(line int) string {
+	for i, node := range v.tunnels {
+		if line >= node.Line && line <= lastLine(node) {
+			return v.config.Tunnels[i].Name
+		}
+	}
+	return ""
+}
+
+// unknownFieldPattern KnownFields 디코딩 오류 중 구조체에 없는 키에 대한 메시지 (yaml.v3 형식)
+var unknownFieldPattern = regexp.MustCompile(`^line (\d+): field (.+) not found in type `)
+
+// checkUnknownKeys 설정 구조체에 없는 키 찾기 (오타로 무시되는 설정 방지)
+//
+// yaml 라이브러리의 KnownFields로 한 번 더 디코딩해서 찾음 (LoadConfig는 알 수 없는 키가 있어도 나머지 설정을 읽음)
+// 디코딩 오류에는 줄 번호만 있으므로 열은 문서 노드에서 같은 줄의 키를 찾아서 채움
+func (v *validator) checkUnknownKeys() {
+	if v.config.source == nil {
+		return
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(v.config.source))
+	decoder.KnownFields(true)
+	var strict Config
+	var typeErr *yaml.TypeError
+	if err := decoder.Decode(&strict); !errors.As(err, &typeErr) {
+		return
+	}
+	for _, message := range typeErr.Errors {
+		// 값 형식 오류는 LoadConfig에서 이미 실패하므로 알 수 없는 키만 확인
+		match := unknownFieldPattern.FindStringSubmatch(message)
+		if match == nil {
+			continue
+		}
+		line, _ := strconv.Atoi(match[1])
+		key := keyNodeAt(v.root, line, match[2])
+		if key == nil {
+			key = &yaml.Node{Line: line}
+		}
+		v.add(key, v.tunnelAt(line), "알 수 없는 키: %s", match[2])
+	}
+}
+
+// checkDuplicateNames 같은 이름의 터널 찾기 (뒤에 나온 터널에 보고)
+func (v *validator) checkDuplicateNames() {
+	first := make(map[string]int)
+	for i, t := range v.config.Tunnels {
+		if t.Name == "" {
+			continue
+		}
+		prev, exists := first[t.Name]
+		if !exists {
+			first[t.Name] = i
+			continue
+		}
+		if node := v.tunnelNode(prev); node != nil {
+			v.add(v.tunnelField(i, "name"), t.Name, "터널 이름이 중복됩니다 (%d번째 줄의 터널과 같은 이름)", node.Line)
+		} else {
+			v.add(v.tunnelField(i, "name"), t.Name, "터널 이름이 중복됩니다")
+		}
+	}
+}
+
+// portOwner 로컬 포트를 먼저 사용한 곳
+type portOwner struct {
+	index  int // 터널 순서 (제어 API면 -1)
+	tunnel string
+	node   *yaml.Node
+}
+
+// String 오류 메시지용 설명
+func (p portOwner) String() string {
+	name := "제어 API"
+	if p.index >= 0 {
+		name = fmt.Sprintf("터널 '%s'", p.tunnel)
+	}
+	if p.node != nil {
+		return fmt.Sprintf("%s(%d번째 줄)", name, p.node.Line)
+	}
+	return name
+}
+
+// checkLocalPorts 활성화된 터널끼리, 또는 제어 API와 로컬 포트가 겹치는지 확인
+// 같은 터널 안의 중복은 TunnelConfig.Validate에서 보고
+func (v *validator) checkLocalPorts() {
+	owners := make(map[int]portOwner)
+	if v.config.API.Enabled {
+		if network, address := v.config.API.GetListen(); network == "tcp" {
+			if _, p, err := net.SplitHostPort(address); err == nil {
+				if port, err := strconv.Atoi(p); err == nil {
+					owners[port] = portOwner{index: -1, node: v.apiNode("listen")}
+				}
+			}
+		}
+	}
+
+	for i, t := range v.config.Tunnels {
+		if !t.Enabled {
+			continue
+		}
+		for j, f := range t.GetForwards() {
+			// remote는 로컬 포트에 리슨하지 않음
+			if f.GetType() == TypeRemote || f.LocalPort <= 0 {
+				continue
+			}
+			owner, exists := owners[f.LocalPort]
+			if !exists {
+				owners[f.LocalPort] = portOwner{index: i, tunnel: t.Name, node: v.forwardField(i, j, "local_port")}
+				continue
+			}
+			if owner.index != i {
+				v.add(v.forwardField(i, j, "local_port"), t.Name, "로컬 포트 %d 중복: %s에서 이미 사용 중", f.LocalPort, owner)
+			}
+		}
+	}
+}
+
+// checkKeyFiles 활성화된 터널의 키 파일 존재 여부 및 권한 확인 (점프 호스트 키 포함)
+func (v *validator) checkKeyFiles() {
+	for i, t := range v.config.Tunnels {
+		if !t.Enabled {
+			continue
+		}
+		if err := checkKeyFile(t.SSHKeyPath); err != nil {
+			v.add(v.tunnelField(i, "ssh_key_path"), t.Name, "%v", err)
+		}
+		for j, jump := range t.JumpHosts {
+			if err := checkKeyFile(jump.KeyPath); err != nil {
+				v.add(v.jumpHostField(i, j, "key_path"), t.Name, "점프 호스트 #%d: %v", j+1, err)
+			}
+		}
+	}
+}
+
+// checkSSHAliases 활성화된 터널의 ssh_alias가 ssh_config(사용자, 시스템 설정 파일)에 있는지 확인
+func (v *validator) checkSSHAliases() {
+	var sshConfigs []*SSHConfig
+	var loadErr error
+	loaded := false
+	for i, t := range v.config.Tunnels {
+		if !t.Enabled || t.SSHAlias == "" {
+			continue
+		}
+		// ssh_alias를 쓰는 터널이 있을 때만 한 번 읽음
+		if !loaded {
+			sshConfigs, loadErr = loadSSHConfigs()
+			loaded = true
+		}
+
+		node := v.tunnelField(i, "ssh_alias")
+		if loadErr != nil {
+			v.add(node, t.Name, "ssh_alias: %v", loadErr)
+			continue
+		}
+		found := false
+		for _, sshConfig := range sshConfigs {
+			if sshConfig.HasHost(t.SSHAlias) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			v.add(node, t.Name, "ssh_config에 Host %s가 없습니다 (%s)", t.SSHAlias, DefaultSSHConfigPath())
+		}
+	}
+}
+
+// fieldNode 매핑 노드의 key 값 노드 (키가 없으면 매핑 노드 자체, 매핑이 아니면 nil)
+func fieldNode(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	if _, value := mappingValue(node, key); value != nil {
+		return value
+	}
+	return node
+}
+
+// itemNode 시퀀스 노드의 i번째 항목 (없으면 nil)
+func itemNode(node *yaml.Node, i int) *yaml.Node {
+	if node == nil || node.Kind != yaml.SequenceNode || i >= len(node.Content) {
+		return nil
+	}
+	return node.Content[i]
+}
+
+// walkPath node에서 path의 키(목록은 순서)를 따라간 값 노드
+// 중간에 없는 키가 있으면 마지막으로 찾은 노드 (node가 nil이면 nil)
+func walkPath(node *yaml.Node, path []string) *yaml.Node {
+	for _, key := range path {
+		var next *yaml.Node
+		switch {
+		case node == nil:
+			return nil
+		case node.Kind == yaml.MappingNode:
+			_, next = mappingValue(node, key)
+		case node.Kind == yaml.SequenceNode:
+			if i, err := strconv.Atoi(key); err == nil {
+				next = itemNode(node, i)
+			}
+		}
+		if next == nil {
+			return node
+		}
+		node = next
+	}
+	return node
+}
+
+// keyNodeAt node 아래에서 line번째 줄에 있는 key 이름의 키 노드 (없으면 nil)
+func keyNodeAt(node *yaml.Node, line int, key string) *yaml.Node {
+	if node == nil {
+		return nil
+	}
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if k := node.Content[i]; k.Line == line && k.Value == key {
+				return k
+			}
+		}
+	}
+	for _, child := range node.Content {
+		if found := keyNodeAt(child, line, key); found != nil {
+			return found
+		}
+	}
+	return nil
+}
@@ -2,8 +2,10 @@ package manager
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"strings"
 	"sync"
 	"time"
 
@@ -11,15 +13,21 @@ import (
 	"tunnels/internal/tunnel"
 )
 
+// ErrTunnelNotFound 설정에 없거나 비활성화된 터널 이름
+var ErrTunnelNotFound = errors.New("터널을 찾을 수 없습니다")
+
 // Manager 터널 매니저
 type Manager struct {
-	tunnels    map[string]*tunnel.Tunnel
-	tunnelOrder []string  // 터널 순서를 유지하기 위한 슬라이스
-	config     *config.Config
-	configPath string
-	mu         sync.RWMutex
-	ctx        context.Context
-	cancel     context.CancelFunc
+	tunnels     map[string]*tunnel.Tunnel
+	tunnelOrder []string // 터널 순서를 유지하기 위한 슬라이스
+	config      *config.Config
+	configPath  string
+	mu          sync.RWMutex
+	ctx         context.Context
+	cancel      context.CancelFunc
+	configHash  string                     // 마지막으로 로드를 시도한 설정 파일 내용의 해시 (변경 감지용)
+	configError string                     // 마지막 설정 로드 실패 원인 (성공하면 빈 문자열)
+	onReload    func(ReloadSummary, error) // Reload로 다시 로드한 뒤 호출 (트레이 메뉴 갱신)
 }
 
 // NewManager 새 매니저 생성
@@ -34,95 +42,185 @@ func NewManager(configPath string) *Manager {
 	}
 }
 
+// ReloadSummary 설정 로드 결과 (터널 이름 목록, 설정 파일 순서)
+type ReloadSummary struct {
+	Added     []string // 새로 추가되어 시작한 터널
+	Removed   []string // 삭제되거나 비활성화되어 중지한 터널
+	Changed   []string // 설정이 바뀌어 재시작한 터널
+	Unchanged []string // 그대로 유지한 터널
+	Skipped   []string // 설정 오류 또는 키 파일 권한 문제로 연결하지 않은 터널
+}
+
+// String 로그용 요약 문자열
+func (s ReloadSummary) String() string {
+	return fmt.Sprintf("추가 %d, 제거 %d, 변경 %d, 유지 %d (건너뜀 %d)",
+		len(s.Added), len(s.Removed), len(s.Changed), len(s.Unchanged), len(s.Skipped))
+}
+
 // LoadConfig 설정 로드 및 터널 업데이트
-func (m *Manager) LoadConfig() error {
-	cfg, err := config.LoadConfig(m.configPath)
+//
+// 바뀐 터널만 다시 시작하고, 설정이 그대로인 터널은 연결을 유지함
+func (m *Manager) LoadConfig() (ReloadSummary, error) {
+	var summary ReloadSummary
+
+	// 같은 내용을 다시 로드하지 않도록 해시 기록 (파싱에 실패한 내용 포함)
+	// 읽는 사이에 파일이 바뀌어도 해시와 설정이 어긋나지 않도록 한 번 읽은 내용을 해시하고 파싱
+	var cfg *config.Config
+	var hash string
+	data, err := config.ReadConfigFile(m.configPath)
+	if err == nil {
+		hash = contentHash(data)
+		cfg, err = config.ParseConfig(data, m.configPath)
+	}
 	if err != nil {
-		return fmt.Errorf("설정 로드 실패: %v", err)
+		err = fmt.Errorf("설정 로드 실패: %v", err)
+		// 이전 설정과 터널은 그대로 유지
+		m.mu.Lock()
+		m.configHash = hash
+		m.configError = err.Error()
+		m.mu.Unlock()
+		return summary, err
 	}
 
-	// 키 파일 권한 확인 (모든 활성화된 터널에 대해)
-	keyPermissionErrors := cfg.CheckAllEnabledKeyFilePermissions()
-	if len(keyPermissionErrors) > 0 {
-		log.Printf("=== SSH 키 파일 권한 확인 결과 ===")
-		for _, err := range keyPermissionErrors {
-			log.Printf("권한 오류: %v", err)
+	// 설정 파일 전체 검사 (터널별 설정, 중복된 이름/포트, 알 수 없는 키, 키 파일 권한)
+	// 문제가 있으면 파싱 실패와 마찬가지로 적용하지 않고 이전 설정과 터널을 그대로 유지
+	if err := cfg.Validate(); err != nil {
+		log.Printf("=== 설정 파일 검사 결과 ===")
+		for _, line := range strings.Split(err.Error(), "\n") {
+			log.Printf("설정 오류: %s", line)
 		}
-		log.Printf("=== 권한 문제로 인해 해당 터널들은 연결되지 않습니다 ===")
+		log.Printf("=== 설정 파일을 고칠 때까지 이전 설정으로 계속 동작합니다 ===")
+
+		err = fmt.Errorf("설정 검사 실패: %v", err)
+		m.mu.Lock()
+		m.configHash = hash
+		m.configError = err.Error()
+		m.mu.Unlock()
+		return summary, err
 	}
 
 	m.mu.Lock()
-	defer m.mu.Unlock()
-
-	// 기존 터널들 중지
-	for name, t := range m.tunnels {
-		t.Stop()
-		delete(m.tunnels, name)
-	}
-
-	// 터널 순서 초기화
-	m.tunnelOrder = make([]string, 0)
-
-	// 새 context 생성 (기존 context가 취소되었을 수 있음)
-	m.ctx, m.cancel = context.WithCancel(context.Background())
 
 	// 설정 업데이트
 	m.config = cfg
+	m.configHash = hash
+	m.configError = ""
 
-	// 새 터널들 생성 및 시작
 	enabledTunnels := cfg.GetEnabledTunnels()
-	successCount := 0
-	skippedCount := 0
+	enabled := make(map[string]bool, len(enabledTunnels))
+	for _, tunnelConfig := range enabledTunnels {
+		enabled[tunnelConfig.Name] = true
+	}
+
+	// 중지와 시작은 전송 계층 종료를 기다리느라 오래 걸릴 수 있으므로 목록만 모아 두고 뮤텍스를 놓은 뒤 수행
+	var stopping, starting []*tunnel.Tunnel
+
+	// 삭제되거나 비활성화된 터널 중지 (기존 순서대로)
+	for _, name := range m.tunnelOrder {
+		if t, exists := m.tunnels[name]; exists && !enabled[name] {
+			stopping = append(stopping, t)
+			delete(m.tunnels, name)
+			summary.Removed = append(summary.Removed, name)
+		}
+	}
+
+	// 터널 순서는 설정 파일 순서를 따름
+	m.tunnelOrder = make([]string, 0, len(enabledTunnels))
+	started := make(map[string]bool, len(enabledTunnels))
 
 	for _, tunnelConfig := range enabledTunnels {
-		// 터널 인스턴스는 항상 생성 (목록 표시를 위해)
-		t := tunnel.NewTunnel(tunnelConfig)
-		m.tunnels[tunnelConfig.Name] = t
-		// 터널 순서 저장 (설정 파일 순서 유지)
+		// 이름이 같은 터널은 먼저 나온 것만 사용 (뒤의 터널이 앞의 터널을 덮어쓰지 않도록)
+		if started[tunnelConfig.Name] {
+			log.Printf("터널 '%s' 이름이 중복되어 건너뜀", tunnelConfig.Name)
+			summary.Skipped = append(summary.Skipped, tunnelConfig.Name)
+			continue
+		}
+		started[tunnelConfig.Name] = true
 		m.tunnelOrder = append(m.tunnelOrder, tunnelConfig.Name)
 
-		// 설정 검증
-		if err := tunnelConfig.Validate(); err != nil {
-			log.Printf("터널 '%s' 설정 오류: %v", tunnelConfig.Name, err)
-			// 터널 인스턴스에 오류 상태 설정
-			t.SetErrorStatus(err.Error())
-			skippedCount++
+		old, exists := m.tunnels[tunnelConfig.Name]
+		switch {
+		case !exists:
+			summary.Added = append(summary.Added, tunnelConfig.Name)
+		case !old.GetConfig().SameConnection(tunnelConfig):
+			stopping = append(stopping, old)
+			summary.Changed = append(summary.Changed, tunnelConfig.Name)
+		case old.GetStatus() == tunnel.StatusError && old.GetNextRetry().IsZero():
+			// 설정은 그대로지만 자동 재시작이 중단된 터널은 다시 시도 (키 파일 권한을 고친 경우 등)
+			stopping = append(stopping, old)
+			summary.Unchanged = append(summary.Unchanged, tunnelConfig.Name)
+		default:
+			// 재시도 정책만 바뀐 경우 연결은 유지하고 다음 재시도부터 새 정책 적용
+			old.UpdateConfig(tunnelConfig)
+			summary.Unchanged = append(summary.Unchanged, tunnelConfig.Name)
 			continue
 		}
 
-		// 키 파일 권한 확인
-		if err := tunnelConfig.CheckKeyFilePermissions(); err != nil {
-			log.Printf("터널 '%s' 키 파일 권한 문제로 연결 건너뜀: %v", tunnelConfig.Name, err)
-			// 터널 인스턴스에 오류 상태 설정
-			t.SetErrorStatus(fmt.Sprintf("키 파일 권한 오류: %v", err))
-			skippedCount++
-			continue
+		if t := m.newTunnel(tunnelConfig); t != nil {
+			starting = append(starting, t)
+		} else {
+			summary.Skipped = append(summary.Skipped, tunnelConfig.Name)
 		}
+	}
+	m.mu.Unlock()
+
+	// 이전 터널이 포트를 놓은 뒤에 새 터널을 시작하도록 모두 중지될 때까지 기다림
+	var wg sync.WaitGroup
+	for _, t := range stopping {
+		wg.Add(1)
+		go func(t *tunnel.Tunnel) {
+			defer wg.Done()
+			t.Stop()
+		}(t)
+	}
+	wg.Wait()
 
-		// 비동기로 터널 시작
+	// 비동기로 터널 시작
+	for _, t := range starting {
 		go func(t *tunnel.Tunnel) {
 			if err := t.Start(); err != nil {
 				log.Printf("터널 '%s' 시작 실패: %v", t.GetConfig().Name, err)
-			} else {
-				successCount++
 			}
 		}(t)
 	}
 
-	// 잠시 대기 후 성공한 터널 수 로그 및 즉시 상태 확인
+	log.Printf("설정 로드 완료: %s", summary)
+	if len(summary.Skipped) > 0 {
+		log.Printf("건너뛴 터널들은 설정 오류 또는 키 파일 권한 문제가 있습니다: %s", strings.Join(summary.Skipped, ", "))
+	}
+
+	// 잠시 대기 후 즉시 상태 확인
 	go func() {
 		time.Sleep(1 * time.Second)
-		log.Printf("설정 로드 완료: %d개 터널 활성화, %d개 터널 건너뜀", successCount, skippedCount)
-
-		if skippedCount > 0 {
-			log.Printf("건너뛴 터널들은 설정 오류 또는 키 파일 권한 문제가 있습니다")
-		}
-
-		// 최초 상태 확인 (즉시 업데이트)
 		m.checkAndReconnect()
 	}()
 
-	return nil
+	return summary, nil
+}
+
+// newTunnel 새 터널 인스턴스를 만들어 등록 (뮤텍스를 잡은 상태에서 호출)
+// 설정 오류나 키 파일 권한 문제로 연결할 수 없으면 오류 상태로 등록하고 nil 반환
+func (m *Manager) newTunnel(tunnelConfig config.TunnelConfig) *tunnel.Tunnel {
+	// 터널 인스턴스는 항상 생성 (목록 표시를 위해)
+	t := tunnel.NewTunnel(tunnelConfig)
+	m.tunnels[tunnelConfig.Name] = t
+
+	// 설정 검증
+	if err := tunnelConfig.Validate(); err != nil {
+		log.Printf("터널 '%s' 설정 오류: %v", tunnelConfig.Name, err)
+		// 터널 인스턴스에 오류 상태 설정
+		t.SetErrorStatus(err.Error())
+		return nil
+	}
+
+	// 키 파일 권한 확인
+	if err := tunnelConfig.CheckKeyFilePermissions(); err != nil {
+		log.Printf("터널 '%s' 키 파일 권한 문제로 연결 건너뜀: %v", tunnelConfig.Name, err)
+		// 터널 인스턴스에 오류 상태 설정
+		t.SetErrorStatus(fmt.Sprintf("키 파일 권한 오류: %v", err))
+		return nil
+	}
+	return t
 }
 
 // StartAll 모든 터널 시작
@@ -131,8 +229,8 @@ func (m *Manager) StartAll() error {
 	defer m.mu.RUnlock()
 
 	var errors []string
-	for name, t := range m.tunnels {
-		if err := t.Start(); err != nil {
+	for _, name := range m.tunnelOrder {
+		if err := startExisting(m.tunnels[name], false); err != nil {
 			errors = append(errors, fmt.Sprintf("%s: %v", name, err))
 		}
 	}
@@ -144,7 +242,7 @@ func (m *Manager) StartAll() error {
 	return nil
 }
 
-// StopAll 모든 터널 중지
+// StopAll 모든 터널 중지 (모니터링과 설정 파일 감시는 계속 동작, 앱을 종료할 때는 Shutdown 사용)
 func (m *Manager) StopAll() error {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -169,20 +267,120 @@ func (m *Manager) StopAll() error {
 
 	// 모든 터널 중지 완료 대기
 	wg.Wait()
+	return nil
+}
 
-	// 매니저 컨텍스트 취소
+// Shutdown 모니터링과 설정 파일 감시를 끝내고 모든 터널 중지 (앱 종료 시 호출, 이후 다시 시작할 수 없음)
+func (m *Manager) Shutdown() error {
+	// 중지하는 동안 모니터링이 다시 연결하거나 설정 파일 감시가 터널을 시작하지 않도록 먼저 취소
 	m.cancel()
-	return nil
+	return m.StopAll()
 }
 
 // RestartAll 모든 터널 재시작
 func (m *Manager) RestartAll() error {
-	// LoadConfig에서 이미 모든 터널을 재시작하므로
+	// LoadConfig에서 설정이 바뀐 터널을 이미 재시작하므로
 	// 여기서는 추가 작업이 필요 없음
 	log.Println("모든 터널 재시작 완료")
 	return nil
 }
 
+// StartTunnel 터널 하나 시작 (오류 상태면 재시도 상태를 초기화하고 다시 시작)
+func (m *Manager) StartTunnel(name string) error {
+	t, err := m.getTunnel(name)
+	if err != nil {
+		return err
+	}
+	return startExisting(t, t.GetStatus() == tunnel.StatusError)
+}
+
+// StopTunnel 터널 하나 중지
+func (m *Manager) StopTunnel(name string) error {
+	t, err := m.getTunnel(name)
+	if err != nil {
+		return err
+	}
+	return t.Stop()
+}
+
+// RestartTunnel 터널 하나 재시작 (자동 재시작이 중단된 터널도 다시 시작)
+func (m *Manager) RestartTunnel(name string) error {
+	t, err := m.getTunnel(name)
+	if err != nil {
+		return err
+	}
+	return startExisting(t, true)
+}
+
+// ResetRetries 오류 상태인 터널의 재시도 횟수를 초기화하고 바로 다시 연결
+// 백오프 대기 중이거나 자동 재시작이 중단된 터널을 처음부터 다시 시도할 때 사용 (오류 상태가 아니면 아무 작업도 하지 않음)
+func (m *Manager) ResetRetries(name string) error {
+	t, err := m.getTunnel(name)
+	if err != nil {
+		return err
+	}
+	if t.GetStatus() != tunnel.StatusError {
+		return nil
+	}
+	return startExisting(t, true)
+}
+
+// SetTunnelEnabled 설정 파일에서 터널을 활성화/비활성화하고 바로 적용 (주석과 서식은 그대로 유지)
+func (m *Manager) SetTunnelEnabled(name string, enabled bool) error {
+	if err := config.SetTunnelEnabled(m.configPath, name, enabled); err != nil {
+		return err
+	}
+	_, err := m.Reload()
+	return err
+}
+
+// CheckTunnel 터널 하나의 연결 상태 즉시 확인
+func (m *Manager) CheckTunnel(name string) error {
+	t, err := m.getTunnel(name)
+	if err != nil {
+		return err
+	}
+	t.CheckConnection()
+	return nil
+}
+
+// getTunnel 이름으로 터널 찾기
+func (m *Manager) getTunnel(name string) (*tunnel.Tunnel, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	t, exists := m.tunnels[name]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrTunnelNotFound, name)
+	}
+	return t, nil
+}
+
+// startExisting 등록된 터널을 설정 검증 후 시작하고 잠시 뒤 상태 확인
+// reset이면 먼저 중지해서 재시도 상태를 초기화 (검증에 실패하면 기존 상태 유지)
+func startExisting(t *tunnel.Tunnel, reset bool) error {
+	tunnelConfig := t.GetConfig()
+	if err := tunnelConfig.Validate(); err != nil {
+		return fmt.Errorf("설정 오류: %v", err)
+	}
+	if err := tunnelConfig.CheckKeyFilePermissions(); err != nil {
+		return fmt.Errorf("키 파일 권한 오류: %v", err)
+	}
+	if reset {
+		t.Stop()
+	}
+	if err := t.Start(); err != nil {
+		return err
+	}
+
+	// 다음 모니터링 주기까지 기다리지 않도록 연결 상태 확인
+	go func() {
+		time.Sleep(1 * time.Second)
+		t.CheckConnection()
+	}()
+	return nil
+}
+
 // GetTunnelStatuses 모든 터널 상태 반환 (순서 보장)
 func (m *Manager) GetTunnelStatuses() []TunnelStatus {
 	m.mu.RLock()
@@ -193,27 +391,86 @@ func (m *Manager) GetTunnelStatuses() []TunnelStatus {
 	// 설정 파일 순서대로 터널 상태 반환
 	for _, name := range m.tunnelOrder {
 		if t, exists := m.tunnels[name]; exists {
+			statuses = append(statuses, newTunnelStatus(name, t))
+		}
+	}
+	return statuses
+}
+
+// GetAllTunnelStatuses 비활성화된 터널을 포함해 설정 파일의 모든 터널 상태 반환 (설정 파일 순서, 트레이 메뉴용)
+func (m *Manager) GetAllTunnelStatuses() []TunnelStatus {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.config == nil {
+		return nil
+	}
+
+	statuses := make([]TunnelStatus, 0, len(m.config.Tunnels))
+	seen := make(map[string]bool, len(m.config.Tunnels))
+	for _, tunnelConfig := range m.config.Tunnels {
+		if seen[tunnelConfig.Name] {
+			continue
+		}
+		seen[tunnelConfig.Name] = true
+
+		if t, exists := m.tunnels[tunnelConfig.Name]; exists {
+			statuses = append(statuses, newTunnelStatus(tunnelConfig.Name, t))
+		} else if !tunnelConfig.Enabled {
 			statuses = append(statuses, TunnelStatus{
-				Name:       name,
-				Status:     t.GetStatus(),
-				Config:     t.GetConfig(),
-				LastError:  t.GetLastError(),
-				LastCheck:  t.GetLastCheck(),
-				Connection: t.GetConnectionString(),
+				Name:     tunnelConfig.Name,
+				Status:   tunnel.StatusDisconnected,
+				Config:   tunnelConfig,
+				ExitCode: -1,
+				Disabled: true,
 			})
 		}
 	}
 	return statuses
 }
 
-// StartMonitoring 연결 상태 모니터링 시작
+// GetTunnelStatus 터널 하나의 상태 반환
+func (m *Manager) GetTunnelStatus(name string) (TunnelStatus, error) {
+	t, err := m.getTunnel(name)
+	if err != nil {
+		return TunnelStatus{}, err
+	}
+	return newTunnelStatus(name, t), nil
+}
+
+// newTunnelStatus 터널 인스턴스의 현재 상태 정보 구성
+func newTunnelStatus(name string, t *tunnel.Tunnel) TunnelStatus {
+	exitCode, exitTime := t.GetExitInfo()
+	return TunnelStatus{
+		Name:       name,
+		Status:     t.GetStatus(),
+		Config:     t.GetConfig(),
+		LastError:  t.GetLastError(),
+		LastCheck:  t.GetLastCheck(),
+		Connection: t.GetConnectionString(),
+		Forwards:   t.GetForwardStatuses(),
+		Failure:    t.GetFailureReason(),
+		Output:     t.GetOutput(),
+		ExitCode:   exitCode,
+		ExitTime:   exitTime,
+		NextRetry:  t.GetNextRetry(),
+	}
+}
+
+// StartMonitoring 연결 상태 모니터링 및 설정 파일 감시 시작
 func (m *Manager) StartMonitoring() {
 	go m.monitorLoop()
+	go m.watchConfig()
 }
 
 // monitorLoop 모니터링 루프
 func (m *Manager) monitorLoop() {
-	ticker := time.NewTicker(m.config.GetCheckIntervalDuration())
+	// 최초 설정 로드에 실패한 경우에도 설정 파일 감시로 복구될 수 있으므로 기본 간격 사용
+	interval := config.DefaultConfig().GetCheckIntervalDuration()
+	if cfg := m.GetConfig(); cfg != nil {
+		interval = cfg.GetCheckIntervalDuration()
+	}
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
 	for {
@@ -227,14 +484,25 @@ func (m *Manager) monitorLoop() {
 }
 
 // checkAndReconnect 연결 상태 확인 및 재연결
+// 프로브가 느린 터널이 다른 터널 확인이나 설정 리로드를 막지 않도록 뮤텍스 밖에서 동시에 확인
 func (m *Manager) checkAndReconnect() {
 	m.mu.RLock()
-	defer m.mu.RUnlock()
-
+	tunnels := make([]*tunnel.Tunnel, 0, len(m.tunnels))
 	for _, t := range m.tunnels {
-		// 연결 상태 확인
-		t.CheckConnection()
+		tunnels = append(tunnels, t)
 	}
+	m.mu.RUnlock()
+
+	var wg sync.WaitGroup
+	for _, t := range tunnels {
+		wg.Add(1)
+		go func(t *tunnel.Tunnel) {
+			defer wg.Done()
+			// 연결 상태 확인
+			t.CheckConnection()
+		}(t)
+	}
+	wg.Wait()
 }
 
 // GetConfigPath 설정 파일 경로 반환
@@ -257,6 +525,13 @@ type TunnelStatus struct {
 	LastError  string
 	LastCheck  time.Time
 	Connection string
+	Forwards   []tunnel.ForwardStatus // 포워딩별 상태 (설정 순서)
+	Failure    tunnel.FailureReason   // 실패 원인 분류 (오류 상태일 때)
+	Output     []string               // ssh 프로세스 stderr 최근 출력
+	ExitCode   int                    // 마지막 ssh 프로세스 종료 코드 (없으면 -1)
+	ExitTime   time.Time              // 마지막 세션 종료 시간
+	NextRetry  time.Time              // 다음 자동 재시작 예정 시간 (예약이 없으면 zero)
+	Disabled   bool                   // 설정에서 비활성화된 터널 (GetAllTunnelStatuses에서만 포함)
 }
 
 // GetHealthyCount 정상 동작 중인 터널 수 반환
@@ -0,0 +1,112 @@
+package manager
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"os"
+	"time"
+)
+
+// 설정 파일 감시 간격
+const (
+	configPollInterval = 1 * time.Second // 설정 파일 변경 확인 간격
+	configDebounce     = 2 * time.Second // 마지막 변경 후 이 시간 동안 내용이 그대로면 적용 (편집기 저장 중 상태 무시)
+)
+
+// SetReloadHandler Reload로 설정을 다시 로드했을 때 호출할 함수 설정
+// (설정 파일 감시나 제어 API처럼 트레이 메뉴 밖에서 다시 로드한 경우 메뉴 갱신용)
+func (m *Manager) SetReloadHandler(handler func(ReloadSummary, error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onReload = handler
+}
+
+// Reload 설정을 다시 로드하고 SetReloadHandler로 등록한 함수에 결과 전달
+func (m *Manager) Reload() (ReloadSummary, error) {
+	summary, err := m.LoadConfig()
+
+	m.mu.RLock()
+	handler := m.onReload
+	m.mu.RUnlock()
+	if handler != nil {
+		handler(summary, err)
+	}
+	return summary, err
+}
+
+// GetConfigError 마지막 설정 로드 실패 원인 반환 (성공했으면 빈 문자열)
+// 실패한 경우 이전 설정으로 계속 동작 중
+func (m *Manager) GetConfigError() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.configError
+}
+
+// watchConfig 설정 파일을 주기적으로 확인해서 내용이 바뀌면 다시 로드
+func (m *Manager) watchConfig() {
+	ticker := time.NewTicker(configPollInterval)
+	defer ticker.Stop()
+
+	var lastModTime time.Time
+	var lastSize int64
+	var pendingHash string     // 적용을 기다리는 새 내용
+	var pendingSince time.Time // pendingHash를 처음 본 시간
+
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		info, err := os.Stat(m.configPath)
+		if err != nil {
+			// 편집기가 파일을 지웠다가 다시 만드는 중일 수 있으므로 다음 확인까지 기다림
+			continue
+		}
+		// 수정 시간과 크기가 그대로면 내용을 읽지 않음
+		if pendingHash == "" && info.ModTime().Equal(lastModTime) && info.Size() == lastSize {
+			continue
+		}
+		lastModTime, lastSize = info.ModTime(), info.Size()
+
+		hash := fileHash(m.configPath)
+		m.mu.RLock()
+		loadedHash := m.configHash
+		m.mu.RUnlock()
+
+		switch {
+		case hash == "" || hash == loadedHash:
+			pendingHash = ""
+			continue
+		case hash != pendingHash:
+			// 새 변경: 저장이 끝날 때까지 대기
+			pendingHash, pendingSince = hash, time.Now()
+			continue
+		case time.Since(pendingSince) < configDebounce:
+			continue
+		}
+		pendingHash = ""
+
+		log.Printf("설정 파일 변경 감지: %s", m.configPath)
+		if _, err := m.Reload(); err != nil {
+			log.Printf("변경된 설정 적용 실패 (이전 설정으로 계속 동작): %v", err)
+		}
+	}
+}
+
+// fileHash 파일 내용의 SHA-256 해시 (읽을 수 없으면 빈 문자열)
+func fileHash(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return contentHash(data)
+}
+
+// contentHash 설정 파일 내용의 SHA-256 해시
+func contentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
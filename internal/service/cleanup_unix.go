@@ -0,0 +1,47 @@
+//go:build !windows
+
+package service
+
+import (
+	"log"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"tunnels/internal/tunnel"
+)
+
+// tunnelSSHPattern 이 앱이 실행한 ssh 프로세스의 명령줄 패턴 (PID가 다른 프로세스에 재사용된 경우를 걸러냄)
+var tunnelSSHPattern = regexp.MustCompile(`^ssh .*-o ServerAliveCountMax=3 .*-N `)
+
+// CleanupSSHProcesses 남은 SSH 프로세스 정리 (시작 시 이전 실행의 잔여 프로세스, 종료 시 중지되지 않은 프로세스)
+// PID 기록 파일에 있는 프로세스만 종료하므로 다른 설정 파일로 실행한 인스턴스나 사용자의 ssh 세션은 건드리지 않음
+func CleanupSSHProcesses(pidFile string) {
+	pids := tunnel.ReadPIDFile(pidFile)
+	if len(pids) == 0 {
+		log.Println("정리할 SSH 프로세스 없음")
+		return
+	}
+
+	killed := 0
+	for _, pid := range pids {
+		out, err := exec.Command("ps", "-p", strconv.Itoa(pid), "-o", "args=").Output()
+		if err != nil || !tunnelSSHPattern.MatchString(strings.TrimSpace(string(out))) {
+			continue
+		}
+		// exec 전송은 ssh를 별도 프로세스 그룹으로 실행하므로 그룹 전체에 신호 전송
+		if err := syscall.Kill(-pid, syscall.SIGTERM); err != nil {
+			if err := syscall.Kill(pid, syscall.SIGTERM); err != nil {
+				log.Printf("SSH 프로세스 %d 종료 실패: %v", pid, err)
+				continue
+			}
+		}
+		killed++
+	}
+	os.Remove(pidFile)
+
+	log.Printf("SSH 프로세스 정리 완료 (%d개 종료)", killed)
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"log"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"tunnels/internal/tunnel"
+)
+
+// CleanupSSHProcesses 남은 SSH 프로세스 정리 (시작 시 이전 실행의 잔여 프로세스, 종료 시 중지되지 않은 프로세스)
+// PID 기록 파일에 있는 프로세스만 종료하므로 다른 설정 파일로 실행한 인스턴스나 사용자의 ssh 세션은 건드리지 않음
+func CleanupSSHProcesses(pidFile string) {
+	pids := tunnel.ReadPIDFile(pidFile)
+	if len(pids) == 0 {
+		log.Println("정리할 SSH 프로세스 없음")
+		return
+	}
+
+	killed := 0
+	for _, pid := range pids {
+		if !isSSHProcess(pid) {
+			continue
+		}
+		process, err := os.FindProcess(pid)
+		if err == nil {
+			err = process.Kill()
+		}
+		if err != nil {
+			log.Printf("SSH 프로세스 %d 종료 실패: %v", pid, err)
+			continue
+		}
+		killed++
+	}
+	os.Remove(pidFile)
+
+	log.Printf("SSH 프로세스 정리 완료 (%d개 종료)", killed)
+}
+
+// isSSHProcess PID가 아직 ssh.exe인지 확인 (PID가 다른 프로세스에 재사용된 경우를 걸러냄)
+func isSSHProcess(pid int) bool {
+	cmd := exec.Command("tasklist", "/FI", "PID eq "+strconv.Itoa(pid), "/FI", "IMAGENAME eq ssh.exe", "/NH", "/FO", "CSV")
+	// 조용히 실행 (출력 숨김)
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		HideWindow:    true,
+		CreationFlags: 0x08000000, // CREATE_NO_WINDOW
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		return false
+	}
+	return strings.Contains(strings.ToLower(string(out)), `"ssh.exe"`)
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"log"
+	"time"
+
+	"tunnels/internal/api"
+	"tunnels/internal/config"
+	"tunnels/internal/manager"
+)
+
+// stopWait 터널 중지 후 ssh 프로세스가 종료되기를 기다리는 시간
+const stopWait = 3 * time.Second
+
+// Service 트레이 앱과 헤드리스 모드가 공유하는 실행 수명 주기
+// (남은 ssh 프로세스 정리, 설정 로드, 모니터링, 제어 API, 종료 처리)
+type Service struct {
+	manager    *manager.Manager
+	configPath string
+	apiServer  *api.Server // 로컬 제어 API (설정에서 활성화한 경우)
+}
+
+// New 새 서비스 생성 (Start 전에 Manager로 다시 로드 핸들러 등을 설정할 수 있음)
+func New(configPath string) *Service {
+	return &Service{
+		manager:    manager.NewManager(configPath),
+		configPath: configPath,
+	}
+}
+
+// Manager 터널 매니저 반환
+func (s *Service) Manager() *manager.Manager {
+	return s.manager
+}
+
+// Start 남은 ssh 프로세스 정리 후 설정 로드, 터널 시작, 모니터링과 제어 API 시작
+// 초기 설정 로드에 실패해도 설정 파일 감시는 시작되어 파일을 고치면 자동 적용됨 (로드 오류 반환)
+func (s *Service) Start() error {
+	CleanupSSHProcesses(config.PIDFilePath(s.configPath))
+
+	_, err := s.manager.LoadConfig()
+	if err != nil {
+		log.Printf("초기 설정 로드 실패: %v", err)
+	}
+
+	// 모니터링 및 설정 파일 감시 시작
+	s.manager.StartMonitoring()
+
+	// 로컬 제어 API 시작 (설정에서 활성화한 경우)
+	s.startAPI()
+
+	return err
+}
+
+// Stop 제어 API 종료, 모든 터널 중지 후 남은 ssh 프로세스 정리
+func (s *Service) Stop() {
+	if s.apiServer != nil {
+		s.apiServer.Close()
+	}
+
+	log.Println("모든 터널 중지 중...")
+
+	// 안전하게 터널 중지
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("터널 중지 중 panic 발생: %v", r)
+			}
+		}()
+		s.manager.Shutdown()
+	}()
+
+	// SSH 프로세스 정리 대기
+	time.Sleep(stopWait)
+
+	// 남은 SSH 프로세스 강제 종료
+	CleanupSSHProcesses(config.PIDFilePath(s.configPath))
+}
+
+// startAPI 설정에서 활성화한 경우 로컬 제어 API 시작 (API 설정 변경은 다시 실행해야 적용됨)
+func (s *Service) startAPI() {
+	cfg := s.manager.GetConfig()
+	if cfg == nil || !cfg.API.Enabled {
+		return
+	}
+
+	server, err := api.Start(s.manager, cfg.API, s.configPath)
+	if err != nil {
+		log.Printf("제어 API 시작 실패: %v", err)
+		return
+	}
+	s.apiServer = server
+}
@@ -0,0 +1,264 @@
+package sshtest
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// 테스트 SSH 서버가 받는 사용자와 패스워드
+const (
+	User     = "tester"
+	Password = "secret"
+)
+
+// Server 테스트용 in-process SSH 서버 (direct-tcpip, tcpip-forward, keepalive만 처리)
+type Server struct {
+	HostKey ssh.Signer // 서버 호스트 키
+	Port    int        // 127.0.0.1에서 리슨 중인 포트
+
+	listener net.Listener
+	config   *ssh.ServerConfig
+
+	mu         sync.Mutex
+	authorized [][]byte // 허용하는 공개키 (ssh wire 형식)
+	conns      []*ssh.ServerConn
+}
+
+// directTCPIP direct-tcpip 채널 요청 (RFC 4254 7.2)
+type directTCPIP struct {
+	Host     string
+	Port     uint32
+	OrigHost string
+	OrigPort uint32
+}
+
+// tcpipForward tcpip-forward 전역 요청 (RFC 4254 7.1)
+type tcpipForward struct {
+	Addr string
+	Port uint32
+}
+
+// forwardedTCPIP forwarded-tcpip 채널 요청 (RFC 4254 7.2)
+type forwardedTCPIP struct {
+	Addr     string
+	Port     uint32
+	OrigAddr string
+	OrigPort uint32
+}
+
+// NewServer 127.0.0.1의 임의 포트에서 SSH 서버 시작 (테스트가 끝나면 종료)
+// User/Password 패스워드 인증과 Authorize로 허용한 공개키 인증을 받음
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+
+	_, hostPriv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("호스트 키 생성 실패: %v", err)
+	}
+	hostKey, err := ssh.NewSignerFromKey(hostPriv)
+	if err != nil {
+		t.Fatalf("호스트 키 변환 실패: %v", err)
+	}
+
+	s := &Server{HostKey: hostKey}
+	s.config = &ssh.ServerConfig{
+		PasswordCallback: func(c ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
+			if c.User() == User && string(password) == Password {
+				return nil, nil
+			}
+			return nil, errors.New("패스워드 불일치")
+		},
+		PublicKeyCallback: func(c ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			for _, k := range s.authorized {
+				if c.User() == User && bytes.Equal(k, key.Marshal()) {
+					return nil, nil
+				}
+			}
+			return nil, errors.New("허용되지 않은 키")
+		},
+	}
+	s.config.AddHostKey(hostKey)
+
+	s.listener, err = net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("SSH 서버 리슨 실패: %v", err)
+	}
+	s.Port = s.listener.Addr().(*net.TCPAddr).Port
+
+	go func() {
+		for {
+			conn, err := s.listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+
+	t.Cleanup(func() {
+		s.listener.Close()
+		s.CloseConns()
+	})
+	return s
+}
+
+// Authorize 공개키 인증 허용
+func (s *Server) Authorize(key ssh.PublicKey) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.authorized = append(s.authorized, key.Marshal())
+}
+
+// CloseConns 서버 쪽에서 모든 SSH 연결 종료 (연결 끊김 재현용)
+func (s *Server) CloseConns() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range s.conns {
+		c.Close()
+	}
+	s.conns = nil
+}
+
+// serve SSH 연결 하나 처리
+func (s *Server) serve(conn net.Conn) {
+	sconn, chans, reqs, err := ssh.NewServerConn(conn, s.config)
+	if err != nil {
+		conn.Close()
+		return
+	}
+	s.mu.Lock()
+	s.conns = append(s.conns, sconn)
+	s.mu.Unlock()
+
+	go s.handleRequests(sconn, reqs)
+
+	for ch := range chans {
+		if ch.ChannelType() != "direct-tcpip" {
+			ch.Reject(ssh.UnknownChannelType, "지원하지 않는 채널")
+			continue
+		}
+		go s.handleDirect(ch)
+	}
+}
+
+// handleDirect direct-tcpip 채널을 요청된 대상에 연결 (로컬 포워딩, SOCKS)
+func (s *Server) handleDirect(ch ssh.NewChannel) {
+	var req directTCPIP
+	if err := ssh.Unmarshal(ch.ExtraData(), &req); err != nil {
+		ch.Reject(ssh.ConnectionFailed, err.Error())
+		return
+	}
+	target, err := net.Dial("tcp", net.JoinHostPort(req.Host, strconv.Itoa(int(req.Port))))
+	if err != nil {
+		ch.Reject(ssh.ConnectionFailed, err.Error())
+		return
+	}
+	channel, reqs, err := ch.Accept()
+	if err != nil {
+		target.Close()
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+	pipeChannel(channel, target)
+}
+
+// handleRequests 전역 요청 처리 (원격 포워딩과 keepalive)
+func (s *Server) handleRequests(sconn *ssh.ServerConn, reqs <-chan *ssh.Request) {
+	var listeners []net.Listener
+	defer func() {
+		for _, l := range listeners {
+			l.Close()
+		}
+	}()
+
+	for req := range reqs {
+		switch req.Type {
+		case "tcpip-forward":
+			var fwd tcpipForward
+			if err := ssh.Unmarshal(req.Payload, &fwd); err != nil {
+				req.Reply(false, nil)
+				continue
+			}
+			l, err := net.Listen("tcp", net.JoinHostPort(fwd.Addr, strconv.Itoa(int(fwd.Port))))
+			if err != nil {
+				req.Reply(false, nil)
+				continue
+			}
+			listeners = append(listeners, l)
+			req.Reply(true, nil)
+			go s.acceptForwarded(sconn, l, fwd)
+		case "keepalive@openssh.com":
+			req.Reply(true, nil)
+		default:
+			if req.WantReply {
+				req.Reply(false, nil)
+			}
+		}
+	}
+}
+
+// acceptForwarded 서버 쪽 리스너로 들어온 연결을 forwarded-tcpip 채널로 클라이언트에 전달
+func (s *Server) acceptForwarded(sconn *ssh.ServerConn, l net.Listener, fwd tcpipForward) {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			origin := conn.RemoteAddr().(*net.TCPAddr)
+			payload := ssh.Marshal(forwardedTCPIP{
+				Addr:     fwd.Addr,
+				Port:     fwd.Port,
+				OrigAddr: origin.IP.String(),
+				OrigPort: uint32(origin.Port),
+			})
+			channel, reqs, err := sconn.OpenChannel("forwarded-tcpip", payload)
+			if err != nil {
+				conn.Close()
+				return
+			}
+			go ssh.DiscardRequests(reqs)
+			pipeChannel(channel, conn)
+		}()
+	}
+}
+
+// pipeChannel SSH 채널과 TCP 연결 사이에 데이터 복사
+func pipeChannel(channel ssh.Channel, conn net.Conn) {
+	defer channel.Close()
+	defer conn.Close()
+
+	done := make(chan struct{}, 2)
+	go func() {
+		io.Copy(channel, conn)
+		channel.CloseWrite()
+		done <- struct{}{}
+	}()
+	go func() {
+		io.Copy(conn, channel)
+		done <- struct{}{}
+	}()
+	<-done
+}
+
+// FreePort 사용하지 않는 로컬 포트 (잠깐 리슨했다가 닫아서 얻음)
+func FreePort(t testing.TB) int {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("빈 포트 찾기 실패: %v", err)
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port
+}
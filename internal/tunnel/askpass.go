@@ -0,0 +1,166 @@
+package tunnel
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"tunnels/internal/config"
+)
+
+// ssh가 실행하는 askpass 헬퍼에 전달하는 환경 변수
+const (
+	askpassAddrEnv  = "TUNNELS_ASKPASS_ADDR"  // 패스워드를 받아올 루프백 주소
+	askpassTokenEnv = "TUNNELS_ASKPASS_TOKEN" // 일회성 인증 토큰
+)
+
+// askpassTimeout 헬퍼 연결 하나를 처리하는 최대 시간
+const askpassTimeout = 5 * time.Second
+
+// askpassServer exec 전송에서 ssh의 패스워드 요청에 응답하는 루프백 서버
+//
+// 패스워드를 명령줄이나 환경 변수에 넣지 않기 위해, ssh가 SSH_ASKPASS로 실행한
+// 이 프로그램(헬퍼 모드)이 토큰을 들고 접속하면 그때만 패스워드를 돌려줌
+type askpassServer struct {
+	config   config.TunnelConfig
+	listener net.Listener
+	token    string
+}
+
+// startAskpassServer askpass 서버 시작 (루프백 임의 포트)
+func startAskpassServer(cfg config.TunnelConfig) (*askpassServer, error) {
+	tokenBytes := make([]byte, 32)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return nil, fmt.Errorf("askpass 토큰 생성 실패: %v", err)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("askpass 서버 시작 실패: %v", err)
+	}
+
+	s := &askpassServer{
+		config:   cfg,
+		listener: listener,
+		token:    hex.EncodeToString(tokenBytes),
+	}
+	go s.serve()
+	return s, nil
+}
+
+// serve 헬퍼 연결 처리 (리스너가 닫힐 때까지)
+func (s *askpassServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+// handle 헬퍼 요청 하나 처리 (요청: 토큰 줄 + 프롬프트 줄, 응답: 패스워드)
+func (s *askpassServer) handle(conn net.Conn) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(askpassTimeout))
+
+	reader := bufio.NewReader(conn)
+	token, err := reader.ReadString('\n')
+	if err != nil {
+		return
+	}
+	if subtle.ConstantTimeCompare([]byte(strings.TrimSpace(token)), []byte(s.token)) != 1 {
+		log.Printf("터널 '%s' askpass 요청 거부: 토큰 불일치", s.config.Name)
+		return
+	}
+	prompt, _ := reader.ReadString('\n')
+	prompt = strings.TrimSpace(prompt)
+
+	if !s.isPasswordPrompt(prompt) {
+		log.Printf("터널 '%s' askpass 요청 거부: 패스워드 요청이 아님 (%s)", s.config.Name, prompt)
+		return
+	}
+	io.WriteString(conn, s.config.SSHPassword)
+}
+
+// isPasswordPrompt ssh_host의 패스워드를 묻는 프롬프트인지 확인
+//
+// 키 암호나 호스트 키 확인 질문에는 답하지 않고,
+// 점프 호스트가 있으면 점프 호스트에 패스워드를 보내지 않도록 호스트 이름까지 확인
+func (s *askpassServer) isPasswordPrompt(prompt string) bool {
+	if !strings.Contains(strings.ToLower(prompt), "password") {
+		return false
+	}
+	if len(s.config.JumpHosts) == 0 {
+		return true
+	}
+	// "user@host's password: " (password) 또는 "(user@host) Password: " (keyboard-interactive)
+	host := "@" + s.config.SSHHost
+	return strings.Contains(prompt, host+"'s password") || strings.Contains(prompt, host+")")
+}
+
+// env ssh 프로세스에 추가할 환경 변수 (이 프로그램을 SSH_ASKPASS로 지정)
+func (s *askpassServer) env() ([]string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("실행 파일 경로 확인 실패: %v", err)
+	}
+
+	env := []string{
+		"SSH_ASKPASS=" + exe,
+		"SSH_ASKPASS_REQUIRE=force", // OpenSSH 8.4+: 터미널 여부와 관계없이 askpass 사용
+		askpassAddrEnv + "=" + s.listener.Addr().String(),
+		askpassTokenEnv + "=" + s.token,
+	}
+	// OpenSSH 8.4 미만은 DISPLAY가 설정된 경우에만 askpass를 사용함
+	if os.Getenv("DISPLAY") == "" {
+		env = append(env, "DISPLAY=:0")
+	}
+	return env, nil
+}
+
+// Close askpass 서버 종료
+func (s *askpassServer) Close() error {
+	return s.listener.Close()
+}
+
+// IsAskpassHelper ssh가 SSH_ASKPASS로 이 프로그램을 실행했는지 여부
+func IsAskpassHelper() bool {
+	return os.Getenv(askpassAddrEnv) != "" && os.Getenv(askpassTokenEnv) != ""
+}
+
+// RunAskpassHelper askpass 헬퍼 모드 실행 (패스워드를 표준 출력으로 ssh에 전달, 종료 코드 반환)
+func RunAskpassHelper(args []string) int {
+	prompt := ""
+	if len(args) > 0 {
+		prompt = args[0]
+	}
+
+	conn, err := net.DialTimeout("tcp", os.Getenv(askpassAddrEnv), askpassTimeout)
+	if err != nil {
+		return 1
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(askpassTimeout))
+
+	// 프롬프트는 한 줄로 전달
+	prompt = strings.ReplaceAll(strings.ReplaceAll(prompt, "\r", " "), "\n", " ")
+	if _, err := fmt.Fprintf(conn, "%s\n%s\n", os.Getenv(askpassTokenEnv), prompt); err != nil {
+		return 1
+	}
+
+	password, err := io.ReadAll(conn)
+	if err != nil || len(password) == 0 {
+		return 1
+	}
+	fmt.Fprintf(os.Stdout, "%s\n", password)
+	return 0
+}
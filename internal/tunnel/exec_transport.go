@@ -0,0 +1,303 @@
+package tunnel
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"strconv"
+	"sync"
+	"time"
+
+	"tunnels/internal/config"
+)
+
+// execTransport OpenSSH 클라이언트(ssh) 프로세스를 실행하는 전송 계층
+type execTransport struct {
+	config   config.TunnelConfig
+	process  *exec.Cmd
+	exited   chan struct{} // 프로세스 종료 시 닫힘
+	exitCode int           // 프로세스 종료 코드 (exited가 닫힌 뒤에만 유효)
+	stopMu   sync.Mutex    // Stop 직렬화 (ctx 취소와 직접 Stop이 겹쳐도 한 번만 종료 신호)
+	output   io.Writer     // ssh stderr 출력 (실패 원인 분석용)
+	// 점프 호스트용 임시 ssh_config 경로 (점프 호스트가 없으면 빈 문자열)
+	jumpConfigPath string
+	// 고정 지문 확인용 임시 known_hosts 경로 (host_key_fingerprint가 없으면 빈 문자열)
+	pinnedKnownHostsPath string
+	// 패스워드 인증용 askpass 서버 (ssh_password가 없으면 nil)
+	askpass *askpassServer
+}
+
+// newExecTransport exec 전송 계층 생성
+func newExecTransport(cfg config.TunnelConfig, output io.Writer) *execTransport {
+	return &execTransport{
+		config:   cfg,
+		exited:   make(chan struct{}),
+		exitCode: -1,
+		output:   output,
+	}
+}
+
+// Start SSH 프로세스 시작
+func (e *execTransport) Start(ctx context.Context) error {
+	// 시작 중에 Stop이 호출되면 프로세스가 시작된 뒤에 종료하도록 직렬화
+	e.stopMu.Lock()
+	defer e.stopMu.Unlock()
+
+	// 고정 지문이 있으면 ssh 실행 전에 호스트 키를 먼저 확인
+	if e.config.HostKeyFingerprint != "" {
+		path, err := writePinnedKnownHosts(e.config)
+		if err != nil {
+			return err
+		}
+		e.pinnedKnownHostsPath = path
+	}
+
+	// 점프 호스트 설정 파일 준비
+	if len(e.config.JumpHosts) > 0 {
+		path, err := writeJumpConfig(e.config)
+		if err != nil {
+			e.cleanup()
+			return err
+		}
+		e.jumpConfigPath = path
+	}
+
+	// SSH 명령어 구성
+	cmd, err := e.buildSSHCommand()
+	if err != nil {
+		e.cleanup()
+		return err
+	}
+
+	// 프로세스 시작 (ctx 취소 시 종료는 아래 goroutine에서 Stop으로 처리)
+	e.process = exec.Command(cmd[0], cmd[1:]...)
+
+	// 패스워드 인증: 이 프로그램을 SSH_ASKPASS로 지정해서 패스워드 전달
+	if e.config.SSHPassword != "" {
+		if err := e.startAskpass(); err != nil {
+			e.cleanup()
+			return err
+		}
+	}
+
+	// 표준 출력은 버리고 (Windows에서는 NUL), 오류 메시지는 실패 원인 분석을 위해 보관
+	e.process.Stdout = nil
+	e.process.Stderr = e.output
+
+	// 플랫폼별 프로세스 속성 (Windows: 콘솔 창 숨김, Unix: 별도 프로세스 그룹)
+	configureProcess(e.process)
+
+	if err := e.process.Start(); err != nil {
+		e.cleanup()
+		return fmt.Errorf("프로세스 시작 실패: %v", err)
+	}
+	// 앱이 비정상 종료되면 다음 실행에서 이 인스턴스가 실행한 프로세스만 정리할 수 있도록 기록
+	pid := e.process.Process.Pid
+	recordPID(e.config.PIDFile, pid)
+
+	// 좀비 프로세스가 남지 않도록 종료 대기 후 종료 코드 기록 (Tunnel이 Done으로 감지)
+	go func() {
+		e.process.Wait()
+		forgetPID(e.config.PIDFile, pid)
+		e.exitCode = e.process.ProcessState.ExitCode()
+		e.cleanup()
+		close(e.exited)
+	}()
+
+	// ctx가 취소되면 프로세스 그룹 종료 (정상 종료 요청 후 강제 종료)
+	go func() {
+		select {
+		case <-ctx.Done():
+			e.Stop()
+		case <-e.exited:
+		}
+	}()
+	return nil
+}
+
+// startAskpass askpass 서버를 시작하고 ssh 프로세스 환경 변수 설정
+func (e *execTransport) startAskpass() error {
+	askpass, err := startAskpassServer(e.config)
+	if err != nil {
+		return err
+	}
+	env, err := askpass.env()
+	if err != nil {
+		askpass.Close()
+		return err
+	}
+	e.askpass = askpass
+	e.process.Env = append(os.Environ(), env...)
+	return nil
+}
+
+// cleanup 점프 호스트 설정, 고정 지문 known_hosts 등 임시 파일 삭제 및 askpass 서버 종료
+func (e *execTransport) cleanup() {
+	if e.askpass != nil {
+		e.askpass.Close()
+	}
+	if e.jumpConfigPath != "" {
+		os.Remove(e.jumpConfigPath)
+	}
+	if e.pinnedKnownHostsPath != "" {
+		os.Remove(e.pinnedKnownHostsPath)
+	}
+}
+
+// Stop SSH 프로세스 종료
+func (e *execTransport) Stop() error {
+	e.stopMu.Lock()
+	defer e.stopMu.Unlock()
+
+	if e.process == nil || e.process.Process == nil {
+		return nil
+	}
+
+	// 이미 종료된 경우 (context 취소 등, 종료된 프로세스 그룹에 신호를 보내지 않음)
+	select {
+	case <-e.exited:
+		return nil
+	default:
+	}
+
+	// 정상 종료 요청 후 대기, 응답이 없으면 강제 종료
+	if err := terminateProcess(e.process.Process); err != nil {
+		log.Printf("터널 '%s' 프로세스 종료 요청 실패: %v", e.config.Name, err)
+	}
+
+	select {
+	case <-e.exited:
+		return nil
+	case <-time.After(2 * time.Second):
+	}
+
+	select {
+	case <-e.exited:
+		return nil
+	default:
+	}
+	if err := killProcess(e.process.Process); err != nil {
+		log.Printf("터널 '%s' 프로세스 강제 종료 실패: %v", e.config.Name, err)
+	}
+
+	// 프로세스 완전 종료 대기
+	select {
+	case <-e.exited:
+	case <-time.After(1 * time.Second):
+	}
+	return nil
+}
+
+// Alive 프로세스가 실행 중인지 여부
+func (e *execTransport) Alive() bool {
+	if e.process == nil || e.process.Process == nil {
+		return false
+	}
+	select {
+	case <-e.exited:
+		return false
+	default:
+		return true
+	}
+}
+
+// Done 프로세스가 종료되면 닫히는 채널
+func (e *execTransport) Done() <-chan struct{} {
+	return e.exited
+}
+
+// ExitCode 프로세스 종료 코드 (실행 중이면 -1, 시그널로 종료된 경우도 -1)
+func (e *execTransport) ExitCode() int {
+	select {
+	case <-e.exited:
+		return e.exitCode
+	default:
+		return -1
+	}
+}
+
+// ForwardError exec 전송은 포워딩별 실패를 알 수 없으므로 항상 nil (상태 확인으로 판단)
+func (e *execTransport) ForwardError(index int) error {
+	return nil
+}
+
+// buildSSHCommand SSH 명령어 구성
+func (e *execTransport) buildSSHCommand() ([]string, error) {
+	// OpenSSH 클라이언트 사용 (Windows 10 1809+ 기본 포함, Linux/macOS 기본 설치)
+	cmd := []string{"ssh"}
+
+	// 포트 설정 (ssh_alias는 지정한 경우에만 ssh_config의 Port를 덮어씀)
+	if port := e.config.SSHPort; port != 0 && (port != 22 || e.config.SSHAlias != "") {
+		cmd = append(cmd, "-p", strconv.Itoa(port))
+	}
+
+	// 점프 호스트 설정 (ProxyJump, 호스트별 설정은 임시 설정 파일에 있음)
+	if e.jumpConfigPath != "" {
+		cmd = append(cmd, "-F", e.jumpConfigPath)
+		cmd = append(cmd, "-J", jumpSpec(e.config))
+	}
+
+	// 포트 포워딩 설정 (여러 포워딩이 하나의 SSH 세션을 공유)
+	exitOnForwardFailure := false
+	for _, f := range e.config.GetForwards() {
+		switch f.GetType() {
+		case config.TypeRemote:
+			remoteForward := fmt.Sprintf("%d:%s:%d", f.RemotePort, f.GetLocalHost(), f.LocalPort)
+			if f.RemoteBindAddress != "" {
+				remoteForward = f.RemoteBindAddress + ":" + remoteForward
+			}
+			cmd = append(cmd, "-R", remoteForward)
+			exitOnForwardFailure = true
+		case config.TypeDynamic:
+			// 로컬 포트에 SOCKS5 프록시 열기 (루프백에만 바인드)
+			cmd = append(cmd, "-D", fmt.Sprintf("127.0.0.1:%d", f.LocalPort))
+		default:
+			localForward := fmt.Sprintf("%d:%s:%d", f.LocalPort, f.RemoteHost, f.RemotePort)
+			cmd = append(cmd, "-L", localForward)
+		}
+	}
+
+	// 원격 포트 바인드 실패는 로컬에서 확인할 방법이 없으므로
+	// 프로세스를 종료시켜 상태 확인에서 감지되도록 함
+	if exitOnForwardFailure {
+		cmd = append(cmd, "-o", "ExitOnForwardFailure=yes")
+	}
+
+	// 키 파일 설정
+	if e.config.SSHKeyPath != "" {
+		cmd = append(cmd, "-i", e.config.SSHKeyPath)
+	}
+
+	// 패스워드 인증 사용 시 (패스워드는 askpass로 전달, 틀리면 다시 묻지 않고 종료)
+	if e.config.SSHPassword != "" {
+		cmd = append(cmd, "-o", "NumberOfPasswordPrompts=1")
+	}
+
+	// 연결 타임아웃 설정
+	cmd = append(cmd, "-o", "ConnectTimeout=10")
+	cmd = append(cmd, "-o", "ServerAliveInterval=20")
+	cmd = append(cmd, "-o", "ServerAliveCountMax=3")
+
+	// 호스트 키 확인 (앱 관리 known_hosts 또는 고정 지문)
+	for _, opt := range sshHostKeyOptions(e.config, e.pinnedKnownHostsPath) {
+		cmd = append(cmd, "-o", opt)
+	}
+
+	// 백그라운드 실행을 위한 옵션
+	cmd = append(cmd, "-N")
+
+	// 사용자@호스트 (ssh_alias는 별칭을 그대로 전달해서 OpenSSH가 ssh_config로 해석, 사용자는 지정한 경우에만)
+	destination := e.config.SSHHost
+	if e.config.SSHAlias != "" {
+		destination = e.config.SSHAlias
+	}
+	if e.config.SSHUser != "" {
+		destination = e.config.SSHUser + "@" + destination
+	}
+	cmd = append(cmd, destination)
+
+	return cmd, nil
+}
@@ -0,0 +1,29 @@
+//go:build !windows
+
+package tunnel
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// nullDevice 출력을 버리는 장치 경로
+const nullDevice = "/dev/null"
+
+// configureProcess 별도 프로세스 그룹으로 실행 (터미널 시그널 분리, 그룹 단위 종료)
+func configureProcess(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Setpgid: true,
+	}
+}
+
+// terminateProcess 프로세스 그룹에 SIGTERM 전송
+func terminateProcess(p *os.Process) error {
+	return syscall.Kill(-p.Pid, syscall.SIGTERM)
+}
+
+// killProcess 프로세스 그룹에 SIGKILL 전송
+func killProcess(p *os.Process) error {
+	return syscall.Kill(-p.Pid, syscall.SIGKILL)
+}
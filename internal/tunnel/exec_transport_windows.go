@@ -0,0 +1,28 @@
+package tunnel
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// nullDevice 출력을 버리는 장치 경로
+const nullDevice = "NUL"
+
+// configureProcess 콘솔 창 없이 실행되도록 프로세스 속성 설정
+func configureProcess(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		HideWindow:    true,
+		CreationFlags: 0x08000000, // CREATE_NO_WINDOW
+	}
+}
+
+// terminateProcess 프로세스 종료 요청 (Windows는 SIGTERM 미지원이므로 바로 Kill)
+func terminateProcess(p *os.Process) error {
+	return p.Kill()
+}
+
+// killProcess 프로세스 강제 종료
+func killProcess(p *os.Process) error {
+	return p.Kill()
+}
@@ -0,0 +1,201 @@
+package tunnel
+
+import (
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// FailureReason 연결 실패 원인 분류
+type FailureReason string
+
+const (
+	FailureNone          FailureReason = ""
+	FailureAuth          FailureReason = "auth"           // 인증 실패 (키, 패스워드, 키 파일 권한)
+	FailureUnreachable   FailureReason = "unreachable"    // SSH 서버에 연결할 수 없음
+	FailurePortInUse     FailureReason = "port_in_use"    // 로컬 포트가 이미 사용 중
+	FailureHostKey       FailureReason = "host_key"       // 호스트 키 불일치 또는 확인 실패
+	FailureRemoteForward FailureReason = "remote_forward" // 서버가 원격 포워딩을 거부
+	FailureProbe         FailureReason = "probe"          // SSH 세션은 정상이지만 원격 대상이 프로브에 응답하지 않음
+	FailureUnknown       FailureReason = "unknown"
+)
+
+// Label 트레이 메뉴에 표시할 상태 태그
+func (r FailureReason) Label() string {
+	switch r {
+	case FailureAuth:
+		return "AUTH ERROR"
+	case FailureUnreachable:
+		return "UNREACHABLE"
+	case FailurePortInUse:
+		return "PORT IN USE"
+	case FailureHostKey:
+		return "HOST KEY MISMATCH"
+	case FailureRemoteForward:
+		return "REMOTE FORWARD REFUSED"
+	case FailureProbe:
+		return "PROBE FAILED"
+	default:
+		return "ERROR"
+	}
+}
+
+// failurePatterns 실패 원인별 메시지 패턴 (ssh stderr, native 전송 오류, 앱 오류 메시지, 소문자로 비교)
+// 앞에 있는 원인이 우선 (호스트 키 문제로 인증까지 실패한 경우 등)
+var failurePatterns = []struct {
+	reason   FailureReason
+	patterns []string
+}{
+	{FailureHostKey, []string{
+		strings.ToLower(ErrHostKeyMismatch.Error()),
+		"remote host identification has changed",
+		"host key verification failed",
+		"you have requested strict checking", // known_hosts에 없는 호스트 (strict)
+		"known_hosts에 등록되지 않은 호스트",
+	}},
+	// 리슨 실패는 권한(1024 미만 포트), 잘못된 주소 등 다른 원인도 있으므로 포트 사용 중인 경우만 분류
+	// (ssh의 "cannot listen to port"와 native 전송의 "리슨 실패"는 원인 문구와 함께 출력됨)
+	{FailurePortInUse, []string{
+		"address already in use",
+		"only one usage of each socket address", // Windows
+		"이미 사용 중",
+	}},
+	{FailureRemoteForward, []string{
+		"remote port forwarding failed",
+		"error: remote port forwarding",
+		"바인드 실패",
+	}},
+	{FailureAuth, []string{
+		"permission denied",
+		"unable to authenticate",
+		"no supported methods remain",
+		"too many authentication failures",
+		"bad permissions",
+		// 앱 오류 메시지는 다른 오류의 일부 단어와 겹치지 않도록 전체 문구로 비교
+		"키 파일 권한 오류",             // 키 파일 권한 확인 실패 (Manager)
+		"ssh 키 파일 읽기 실패",         // native 전송 키 로드 실패
+		"ssh 키 파일 파싱 실패",         // native 전송 키 형식 오류 또는 패스프레이즈 불일치
+		"사용 가능한 ssh 인증 방식이 없습니다", // native 전송 인증 수단 없음
+	}},
+	{FailureUnreachable, []string{
+		"could not resolve hostname",
+		"no such host",
+		"connection refused",
+		"connection timed out",
+		"operation timed out",
+		"i/o timeout",
+		"no route to host",
+		"network is unreachable",
+		"connection reset",
+		"connection closed by",
+	}},
+}
+
+// classifyFailure 오류 메시지와 ssh 출력으로 실패 원인 분류
+func classifyFailure(messages ...string) FailureReason {
+	text := strings.ToLower(strings.Join(messages, "\n"))
+	if strings.TrimSpace(text) == "" {
+		return FailureNone
+	}
+	for _, p := range failurePatterns {
+		for _, pattern := range p.patterns {
+			if strings.Contains(text, pattern) {
+				return p.reason
+			}
+		}
+	}
+	return FailureUnknown
+}
+
+// 출력 버퍼 크기 제한
+const (
+	outputBufferLines   = 50  // 보관할 최대 줄 수
+	outputBufferLineMax = 512 // 줄 하나의 최대 길이
+)
+
+// outputBuffer ssh 프로세스 stderr를 최근 N줄만 보관하는 링 버퍼 (io.Writer)
+type outputBuffer struct {
+	mu      sync.Mutex
+	lines   []string
+	partial []byte // 아직 줄바꿈이 오지 않은 마지막 줄
+}
+
+// newOutputBuffer 출력 버퍼 생성
+func newOutputBuffer() *outputBuffer {
+	return &outputBuffer{}
+}
+
+// Write 출력을 줄 단위로 나눠 보관 (오래된 줄부터 버림)
+func (b *outputBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, c := range p {
+		if c == '\n' {
+			b.flushLine()
+			continue
+		}
+		if len(b.partial) < outputBufferLineMax {
+			b.partial = append(b.partial, c)
+		}
+	}
+	return len(p), nil
+}
+
+// flushLine 현재 줄을 버퍼에 추가 (뮤텍스를 잡은 상태에서 호출)
+func (b *outputBuffer) flushLine() {
+	line := strings.TrimSpace(lineString(b.partial))
+	b.partial = b.partial[:0]
+	if line == "" {
+		return
+	}
+	b.lines = append(b.lines, line)
+	if len(b.lines) > outputBufferLines {
+		b.lines = b.lines[len(b.lines)-outputBufferLines:]
+	}
+}
+
+// lineString 줄 바이트를 문자열로 변환 (최대 길이에서 잘린 경우 끝에 남은 불완전한 UTF-8 문자는 버림)
+func lineString(line []byte) string {
+	if len(line) >= outputBufferLineMax {
+		start := len(line) - 1
+		for start > 0 && !utf8.RuneStart(line[start]) {
+			start--
+		}
+		if !utf8.FullRune(line[start:]) {
+			line = line[:start]
+		}
+	}
+	return string(line)
+}
+
+// Lines 보관 중인 줄 목록 (줄바꿈이 오지 않은 마지막 줄 포함)
+func (b *outputBuffer) Lines() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	lines := append([]string(nil), b.lines...)
+	if line := strings.TrimSpace(lineString(b.partial)); line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// LastLine 마지막 출력 줄 (known_hosts 추가 안내 같은 정보성 메시지는 제외, 없으면 빈 문자열)
+func (b *outputBuffer) LastLine() string {
+	lines := b.Lines()
+	for i := len(lines) - 1; i >= 0; i-- {
+		if !strings.HasPrefix(lines[i], "Warning: Permanently added") {
+			return lines[i]
+		}
+	}
+	return ""
+}
+
+// Reset 버퍼 비우기 (새 연결 시도 시작 시)
+func (b *outputBuffer) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.lines = nil
+	b.partial = b.partial[:0]
+}
@@ -0,0 +1,124 @@
+package tunnel
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"tunnels/internal/config"
+)
+
+// healthCheckTimeout 상태 확인 타임아웃
+const healthCheckTimeout = 5 * time.Second
+
+// ForwardStatus 포워딩별 상태 정보
+type ForwardStatus struct {
+	Label     string
+	Config    config.ForwardConfig
+	Status    Status
+	LastError string
+	Latency   time.Duration // 마지막 상태 확인(프로브)에 걸린 시간
+}
+
+// checkHealth 모든 포워딩 상태 확인 (프로브가 오래 걸릴 수 있으므로 뮤텍스 밖에서 스냅샷으로 호출)
+// 일부 포워딩만 실패한 경우 세션은 정상으로 보고 포워딩 상태에만 기록하며,
+// 모든 포워딩이 실패한 경우에만 오류를 반환
+func checkHealth(forwards []config.ForwardConfig, transport Transport) ([]ForwardStatus, error) {
+	statuses := make([]ForwardStatus, len(forwards))
+
+	healthy := 0
+	var firstErr error
+	for i, f := range forwards {
+		statuses[i] = ForwardStatus{Label: f.Label(), Config: f, Status: StatusConnected}
+		started := time.Now()
+		err := checkForward(transport, i, f)
+		statuses[i].Latency = time.Since(started)
+		if err != nil {
+			statuses[i].Status = StatusError
+			statuses[i].LastError = err.Error()
+			// 세션 문제가 프로브 실패에 가려지지 않도록 프로브 외의 오류를 우선
+			if firstErr == nil || (errors.Is(firstErr, errProbeFailed) && !errors.Is(err, errProbeFailed)) {
+				firstErr = err
+			}
+			continue
+		}
+		healthy++
+	}
+
+	if healthy == 0 {
+		if len(forwards) > 1 {
+			return statuses, fmt.Errorf("모든 포워딩 실패: %w", firstErr)
+		}
+		return statuses, firstErr
+	}
+	return statuses, nil
+}
+
+// checkForward 포워딩 종류에 맞는 방식으로 상태 확인
+func checkForward(transport Transport, index int, f config.ForwardConfig) error {
+	if transport != nil {
+		if err := transport.ForwardError(index); err != nil {
+			return err
+		}
+	}
+
+	switch f.GetType() {
+	case config.TypeRemote:
+		// 원격 포워딩은 SSH 서버 쪽에서 리슨하므로 로컬 포트 확인이 의미 없음
+		// 전송 계층이 살아 있는지로 판단 (exec는 ExitOnForwardFailure로 바인드 실패 시 종료됨)
+		if transport == nil || !transport.Alive() {
+			return fmt.Errorf("원격 포워딩 %s:%d SSH 세션 종료됨", remoteBindAddress(f), f.RemotePort)
+		}
+	case config.TypeDynamic:
+		// 포트가 열려 있는 것만으로는 부족하므로 실제 SOCKS5 인사로 확인
+		if err := checkSocksProxy(f.LocalPort); err != nil {
+			return err
+		}
+	default:
+		// 프로브가 있으면 프로브가 연결부터 확인
+		if f.Probe == nil {
+			return checkLocalPort(f.LocalPort)
+		}
+	}
+
+	// 포트가 열려 있어도 원격 대상이 죽어 있을 수 있으므로 설정된 프로브로 실제 응답 확인
+	if f.Probe != nil {
+		return runProbe(f.Probe, f.LocalPort)
+	}
+	return nil
+}
+
+// checkLocalPort 로컬 포트가 열려있는지 확인
+func checkLocalPort(port int) error {
+	conn, err := net.DialTimeout("tcp", fmt.Sprintf("127.0.0.1:%d", port), healthCheckTimeout)
+	if err != nil {
+		return fmt.Errorf("로컬 포트 %d 연결 실패: %v", port, err)
+	}
+	conn.Close()
+	return nil
+}
+
+// GetForwardStatuses 포워딩별 상태 반환 (설정 순서)
+func (t *Tunnel) GetForwardStatuses() []ForwardStatus {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	// 세션이 연결된 상태면 마지막 확인 결과 사용
+	if (t.status == StatusConnected || t.status == StatusDegraded) && len(t.forwards) > 0 {
+		statuses := make([]ForwardStatus, len(t.forwards))
+		copy(statuses, t.forwards)
+		return statuses
+	}
+
+	// 그 외에는 터널 상태를 그대로 따름
+	forwards := t.config.GetForwards()
+	statuses := make([]ForwardStatus, len(forwards))
+	for i, f := range forwards {
+		statuses[i] = ForwardStatus{Label: f.Label(), Config: f, Status: t.status}
+		if t.status == StatusError && i < len(t.forwards) {
+			statuses[i].LastError = t.forwards[i].LastError
+		}
+	}
+	return statuses
+}
@@ -0,0 +1,198 @@
+package tunnel
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"tunnels/internal/config"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// ErrHostKeyMismatch 호스트 키가 known_hosts 또는 고정 지문과 다름 (재시도하지 않음)
+var ErrHostKeyMismatch = errors.New("호스트 키 불일치")
+
+// errHostKeyFetched 호스트 키만 받고 핸드셰이크를 중단하기 위한 내부 오류
+var errHostKeyFetched = errors.New("호스트 키 수신 완료")
+
+// knownHostsMu 여러 터널이 같은 known_hosts 파일에 동시에 쓰지 않도록 보호
+var knownHostsMu sync.Mutex
+
+// hostKeyCallback 터널 설정의 호스트 키 정책에 맞는 확인 함수 생성 (native 전송용)
+func hostKeyCallback(cfg config.TunnelConfig) ssh.HostKeyCallback {
+	policy := cfg.GetHostKeyPolicy()
+
+	// 점프 호스트에는 고정 지문을 적용하지 않고 known_hosts 정책을 따름
+	finalHost := net.JoinHostPort(cfg.SSHHost, strconv.Itoa(cfg.SSHPort))
+
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		if cfg.HostKeyFingerprint != "" && hostname == finalHost {
+			return checkFingerprint(cfg.HostKeyFingerprint, hostname, key)
+		}
+		if policy == config.HostKeyOff {
+			return nil
+		}
+		return checkKnownHost(cfg.KnownHostsFile, policy == config.HostKeyAcceptNew, hostname, remote, key)
+	}
+}
+
+// checkFingerprint 호스트 키가 고정 지문과 같은지 확인
+func checkFingerprint(fingerprint, hostname string, key ssh.PublicKey) error {
+	if actual := ssh.FingerprintSHA256(key); actual != fingerprint {
+		return fmt.Errorf("%w: %s (기대값 %s, 실제 %s)", ErrHostKeyMismatch, hostname, fingerprint, actual)
+	}
+	return nil
+}
+
+// checkKnownHost known_hosts 파일로 호스트 키 확인 (acceptNew면 처음 보는 호스트를 추가)
+func checkKnownHost(path string, acceptNew bool, hostname string, remote net.Addr, key ssh.PublicKey) error {
+	knownHostsMu.Lock()
+	defer knownHostsMu.Unlock()
+
+	if err := ensureKnownHostsFile(path); err != nil {
+		return err
+	}
+
+	callback, err := knownhosts.New(path)
+	if err != nil {
+		return fmt.Errorf("known_hosts 파일 읽기 실패: %v", err)
+	}
+
+	err = callback(hostname, remote, key)
+	if err == nil {
+		return nil
+	}
+
+	var keyErr *knownhosts.KeyError
+	if !errors.As(err, &keyErr) {
+		return err
+	}
+
+	// 등록된 키가 있는데 다른 경우 (키 변경 또는 중간자 공격 가능성)
+	if len(keyErr.Want) > 0 {
+		return fmt.Errorf("%w: %s (%s:%d에 등록된 키와 다름, 받은 키 %s)",
+			ErrHostKeyMismatch, hostname, keyErr.Want[0].Filename, keyErr.Want[0].Line, ssh.FingerprintSHA256(key))
+	}
+
+	// 처음 보는 호스트
+	if !acceptNew {
+		return fmt.Errorf("known_hosts에 등록되지 않은 호스트: %s (%s, 받은 키 %s)", hostname, path, ssh.FingerprintSHA256(key))
+	}
+	return appendKnownHost(path, hostname, key)
+}
+
+// ensureKnownHostsFile known_hosts 파일이 없으면 빈 파일 생성
+func ensureKnownHostsFile(path string) error {
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("known_hosts 디렉토리 생성 실패: %v", err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("known_hosts 파일 생성 실패: %v", err)
+	}
+	return f.Close()
+}
+
+// appendKnownHost known_hosts 파일에 호스트 키 추가
+func appendKnownHost(path, hostname string, key ssh.PublicKey) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("known_hosts 파일 열기 실패: %v", err)
+	}
+	defer f.Close()
+
+	line := knownhosts.Line([]string{knownhosts.Normalize(hostname)}, key)
+	if _, err := f.WriteString(line + "\n"); err != nil {
+		return fmt.Errorf("known_hosts 파일 저장 실패: %v", err)
+	}
+	return nil
+}
+
+// fetchHostKey 인증 없이 핸드셰이크만 수행해서 서버의 호스트 키를 받아옴
+func fetchHostKey(addr string) (ssh.PublicKey, error) {
+	var hostKey ssh.PublicKey
+	clientConfig := &ssh.ClientConfig{
+		User: "tunnels",
+		HostKeyCallback: func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+			hostKey = key
+			return errHostKeyFetched
+		},
+		Timeout: nativeConnectTimeout,
+	}
+
+	conn, err := net.DialTimeout("tcp", addr, nativeConnectTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("SSH 서버 %s 연결 실패: %v", addr, err)
+	}
+	defer conn.Close()
+
+	conn.SetDeadline(time.Now().Add(nativeConnectTimeout))
+	_, _, _, err = ssh.NewClientConn(conn, addr, clientConfig)
+	if hostKey == nil {
+		return nil, fmt.Errorf("SSH 서버 %s 호스트 키 수신 실패: %v", addr, err)
+	}
+	return hostKey, nil
+}
+
+// writePinnedKnownHosts 고정 지문을 확인한 호스트 키만 담은 임시 known_hosts 생성 (exec 전송용)
+//
+// ssh 클라이언트는 지문 고정을 지원하지 않으므로 먼저 키를 받아 지문을 확인한 뒤,
+// 그 키만 들어 있는 known_hosts와 StrictHostKeyChecking=yes로 실행
+func writePinnedKnownHosts(cfg config.TunnelConfig) (string, error) {
+	addr := net.JoinHostPort(cfg.SSHHost, strconv.Itoa(cfg.SSHPort))
+	key, err := fetchHostKey(addr)
+	if err != nil {
+		return "", err
+	}
+	if err := checkFingerprint(cfg.HostKeyFingerprint, addr, key); err != nil {
+		return "", err
+	}
+
+	f, err := os.CreateTemp("", "tunnels-known-hosts-*")
+	if err != nil {
+		return "", fmt.Errorf("임시 known_hosts 생성 실패: %v", err)
+	}
+	defer f.Close()
+
+	line := knownhosts.Line([]string{knownhosts.Normalize(addr)}, key)
+	if _, err := f.WriteString(line + "\n"); err != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("임시 known_hosts 저장 실패: %v", err)
+	}
+	return f.Name(), nil
+}
+
+// sshHostKeyOptions exec 전송의 호스트 키 확인 관련 ssh 옵션 (-o 값)
+func sshHostKeyOptions(cfg config.TunnelConfig, pinnedKnownHosts string) []string {
+	if pinnedKnownHosts != "" {
+		return []string{
+			"StrictHostKeyChecking=yes",
+			"UserKnownHostsFile=" + quoteSSHPath(pinnedKnownHosts),
+		}
+	}
+
+	switch cfg.GetHostKeyPolicy() {
+	case config.HostKeyOff:
+		// 호스트 키 확인 비활성화 (개발용)
+		return []string{"StrictHostKeyChecking=no", "UserKnownHostsFile=" + nullDevice}
+	case config.HostKeyStrict:
+		return []string{"StrictHostKeyChecking=yes", "UserKnownHostsFile=" + quoteSSHPath(cfg.KnownHostsFile)}
+	default:
+		return []string{"StrictHostKeyChecking=accept-new", "UserKnownHostsFile=" + quoteSSHPath(cfg.KnownHostsFile)}
+	}
+}
+
+// quoteSSHPath ssh 옵션 값으로 쓸 경로 (공백이 있어도 되도록 따옴표, 구분자는 /로 통일)
+func quoteSSHPath(path string) string {
+	return "\"" + filepath.ToSlash(path) + "\""
+}
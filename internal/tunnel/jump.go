@@ -0,0 +1,80 @@
+package tunnel
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"tunnels/internal/config"
+)
+
+// jumpHostAlias 임시 ssh_config에서 점프 호스트를 가리키는 별칭
+func jumpHostAlias(index int) string {
+	return fmt.Sprintf("tunnels-jump-%d", index)
+}
+
+// writeJumpConfig 점프 호스트용 임시 ssh_config 파일 생성
+//
+// ProxyJump(-J)로 실행되는 하위 ssh 프로세스에는 -i, -o 같은 명령줄 옵션이 전달되지 않지만
+// -F 설정 파일은 전달되므로, 점프 호스트별 사용자/포트/키 파일을 설정 파일로 지정함
+func writeJumpConfig(cfg config.TunnelConfig) (string, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Tunnels 터널 '%s'의 점프 호스트 설정 (자동 생성, 터널 중지 시 삭제됨)\n", cfg.Name)
+
+	for i, j := range cfg.JumpHosts {
+		fmt.Fprintf(&b, "\nHost %s\n", jumpHostAlias(i))
+		fmt.Fprintf(&b, "    HostName %s\n", j.Host)
+		fmt.Fprintf(&b, "    Port %d\n", j.GetPort())
+		fmt.Fprintf(&b, "    User %s\n", j.GetUser(cfg.SSHUser))
+		if keyPath := jumpKeyPath(cfg, j); keyPath != "" {
+			fmt.Fprintf(&b, "    IdentityFile \"%s\"\n", filepath.ToSlash(keyPath))
+		}
+		fmt.Fprintf(&b, "    ConnectTimeout 10\n")
+		// 호스트 키 확인 (최종 호스트와 같은 정책 및 known_hosts)
+		for _, opt := range sshHostKeyOptions(cfg, "") {
+			fmt.Fprintf(&b, "    %s\n", strings.Replace(opt, "=", " ", 1))
+		}
+	}
+
+	// 최종 호스트 등 나머지는 사용자의 기본 설정을 그대로 따름
+	fmt.Fprintf(&b, "\nHost *\n    Include ~/.ssh/config\n")
+
+	f, err := os.CreateTemp("", "tunnels-jump-*.conf")
+	if err != nil {
+		return "", fmt.Errorf("점프 호스트 설정 파일 생성 실패: %v", err)
+	}
+	defer f.Close()
+
+	if _, err := f.WriteString(b.String()); err != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("점프 호스트 설정 파일 저장 실패: %v", err)
+	}
+	return f.Name(), nil
+}
+
+// jumpSpec -J 옵션 값 (임시 설정 파일의 별칭을 순서대로 나열)
+func jumpSpec(cfg config.TunnelConfig) string {
+	aliases := make([]string, len(cfg.JumpHosts))
+	for i := range cfg.JumpHosts {
+		aliases[i] = jumpHostAlias(i)
+	}
+	return strings.Join(aliases, ",")
+}
+
+// jumpKeyPath 점프 호스트 키 파일 (미지정 시 터널의 키 파일)
+func jumpKeyPath(cfg config.TunnelConfig, j config.JumpHostConfig) string {
+	if j.KeyPath != "" {
+		return j.KeyPath
+	}
+	return cfg.SSHKeyPath
+}
+
+// jumpString 연결 문자열에 표시할 점프 경로
+func jumpString(cfg config.TunnelConfig) string {
+	hops := make([]string, len(cfg.JumpHosts))
+	for i, j := range cfg.JumpHosts {
+		hops[i] = fmt.Sprintf("%s@%s:%d", j.GetUser(cfg.SSHUser), j.Host, j.GetPort())
+	}
+	return strings.Join(hops, " -> ")
+}
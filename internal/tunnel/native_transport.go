@@ -0,0 +1,498 @@
+package tunnel
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"tunnels/internal/config"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+const (
+	nativeConnectTimeout    = 10 * time.Second // exec 전송의 ConnectTimeout=10과 동일
+	nativeKeepAliveInterval = 20 * time.Second // ServerAliveInterval=20과 동일
+	nativeKeepAliveMaxFails = 3                // ServerAliveCountMax=3과 동일
+)
+
+// nativeTransport Go 내장 SSH 클라이언트로 직접 연결하는 전송 계층
+type nativeTransport struct {
+	config          config.TunnelConfig
+	hostKeyCallback ssh.HostKeyCallback
+	client          *ssh.Client
+	jumpClients     []*ssh.Client // 점프 호스트 연결 (순서대로)
+	forwards        []*nativeForward
+	mu              sync.Mutex // client, jumpClients, forwards 보호
+	agentConn       net.Conn
+	wg              sync.WaitGroup
+	started         atomic.Bool // 연결 및 리슨 완료 여부
+	done            chan struct{}
+	closeOnce       sync.Once
+}
+
+// nativeForward 하나의 SSH 세션 위에서 동작하는 포워딩
+type nativeForward struct {
+	config     config.ForwardConfig
+	listener   net.Listener
+	target     string                                       // 포워딩 대상 주소 (dynamic은 연결마다 SOCKS5로 결정)
+	dialTarget func(network, addr string) (net.Conn, error) // 포워딩 대상 연결 함수
+	err        error                                        // 리슨/바인드 실패 원인
+}
+
+// newNativeTransport native 전송 계층 생성
+func newNativeTransport(cfg config.TunnelConfig) *nativeTransport {
+	return &nativeTransport{
+		config: cfg,
+		done:   make(chan struct{}),
+		// 호스트 키 정책 (known_hosts 또는 고정 지문)
+		hostKeyCallback: hostKeyCallback(cfg),
+	}
+}
+
+// Start SSH 서버에 연결하고 로컬 포트 포워딩 시작
+func (n *nativeTransport) Start(ctx context.Context) error {
+	if n.config.UseAgent {
+		if err := n.openAgent(); err != nil {
+			return err
+		}
+	}
+
+	client, jumpClients, err := n.dial(ctx)
+	n.closeAgent() // 인증이 끝나면 에이전트 연결은 더 이상 필요 없음
+	if err != nil {
+		return err
+	}
+
+	// 포워딩별로 리슨 (일부 실패는 포워딩 상태로 보고하고, 전부 실패한 경우만 시작 실패)
+	var forwards []*nativeForward
+	var firstErr error
+	listening := 0
+	for _, f := range n.config.GetForwards() {
+		fw := &nativeForward{config: f}
+		if fw.err = n.listen(client, fw); fw.err != nil {
+			log.Printf("터널 '%s' 포워딩 %s 시작 실패: %v", n.config.Name, f.Label(), fw.err)
+			if firstErr == nil {
+				firstErr = fw.err
+			}
+		} else {
+			listening++
+		}
+		forwards = append(forwards, fw)
+	}
+
+	n.mu.Lock()
+	n.client = client
+	n.jumpClients = jumpClients
+	n.forwards = forwards
+	n.mu.Unlock()
+
+	if listening == 0 {
+		n.close()
+		return firstErr
+	}
+
+	// 시작 중에 Stop이 호출된 경우
+	select {
+	case <-n.done:
+		n.close()
+		return fmt.Errorf("연결 중 중지됨")
+	default:
+	}
+
+	n.started.Store(true)
+
+	for _, fw := range n.forwards {
+		if fw.listener != nil {
+			n.wg.Add(1)
+			go n.acceptLoop(fw)
+		}
+	}
+
+	go n.keepAlive()
+
+	// SSH 연결이 끊기거나 ctx가 취소되면 리스너도 닫아서 상태 확인에서 감지되도록 함
+	go func() {
+		waitCh := make(chan struct{})
+		go func() {
+			client.Wait()
+			close(waitCh)
+		}()
+
+		select {
+		case <-ctx.Done():
+		case <-waitCh:
+			log.Printf("터널 '%s' SSH 연결 종료됨", n.config.Name)
+		}
+		n.close()
+	}()
+
+	return nil
+}
+
+// listen 포워딩 종류에 따라 리스너와 포워딩 대상 구성
+func (n *nativeTransport) listen(client *ssh.Client, fw *nativeForward) error {
+	f := fw.config
+	switch f.GetType() {
+	case config.TypeRemote:
+		// SSH 서버에서 리슨하고 로컬 대상으로 연결
+		bind := remoteBindAddress(f)
+		if bind == "*" {
+			bind = "0.0.0.0"
+		}
+		addr := net.JoinHostPort(bind, strconv.Itoa(f.RemotePort))
+		listener, err := client.Listen("tcp", addr)
+		if err != nil {
+			return fmt.Errorf("원격 포트 %s 바인드 실패: %v", addr, err)
+		}
+		fw.listener = listener
+		fw.target = net.JoinHostPort(f.GetLocalHost(), strconv.Itoa(f.LocalPort))
+		fw.dialTarget = (&net.Dialer{Timeout: nativeConnectTimeout}).Dial
+	case config.TypeDynamic:
+		// 로컬에서 SOCKS5 프록시로 리슨하고 요청된 대상으로 SSH를 통해 연결
+		listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", f.LocalPort))
+		if err != nil {
+			return fmt.Errorf("로컬 포트 %d 리슨 실패: %v", f.LocalPort, err)
+		}
+		fw.listener = listener
+		fw.dialTarget = client.Dial
+	default:
+		// 로컬에서 리슨하고 SSH를 통해 원격 대상으로 연결
+		listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", f.LocalPort))
+		if err != nil {
+			return fmt.Errorf("로컬 포트 %d 리슨 실패: %v", f.LocalPort, err)
+		}
+		fw.listener = listener
+		fw.target = net.JoinHostPort(f.RemoteHost, strconv.Itoa(f.RemotePort))
+		fw.dialTarget = client.Dial
+	}
+	return nil
+}
+
+// Stop 연결 및 포워딩 종료
+func (n *nativeTransport) Stop() error {
+	n.close()
+	n.wg.Wait()
+	return nil
+}
+
+// Alive SSH 연결이 살아 있는지 여부
+func (n *nativeTransport) Alive() bool {
+	if !n.started.Load() {
+		return false
+	}
+	select {
+	case <-n.done:
+		return false
+	default:
+		return true
+	}
+}
+
+// Done SSH 연결이 끊기거나 Stop되면 닫히는 채널
+func (n *nativeTransport) Done() <-chan struct{} {
+	return n.done
+}
+
+// ExitCode native 전송에는 프로세스가 없으므로 항상 -1
+func (n *nativeTransport) ExitCode() int {
+	return -1
+}
+
+// ForwardError 리슨/바인드에 실패한 포워딩의 오류 반환
+func (n *nativeTransport) ForwardError(index int) error {
+	if !n.started.Load() || index < 0 || index >= len(n.forwards) {
+		return nil
+	}
+	return n.forwards[index].err
+}
+
+// close 리스너와 SSH 연결 닫기 (여러 번 호출해도 안전)
+func (n *nativeTransport) close() {
+	n.closeOnce.Do(func() {
+		close(n.done)
+	})
+
+	// Start 도중 닫힌 경우를 위해 매번 정리 (Close는 여러 번 호출해도 안전)
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for _, fw := range n.forwards {
+		if fw.listener != nil {
+			fw.listener.Close()
+		}
+	}
+	if n.client != nil {
+		n.client.Close()
+	}
+	// 바깥쪽 점프 호스트부터 안쪽 순서로 닫기
+	for i := len(n.jumpClients) - 1; i >= 0; i-- {
+		n.jumpClients[i].Close()
+	}
+}
+
+// dial 점프 호스트를 순서대로 거쳐 SSH 서버에 연결 (각 단계마다 핸드셰이크 및 인증 수행)
+func (n *nativeTransport) dial(ctx context.Context) (*ssh.Client, []*ssh.Client, error) {
+	var jumpClients []*ssh.Client
+	closeJumps := func() {
+		for i := len(jumpClients) - 1; i >= 0; i-- {
+			jumpClients[i].Close()
+		}
+	}
+
+	for i, j := range n.config.JumpHosts {
+		addr := net.JoinHostPort(j.Host, strconv.Itoa(j.GetPort()))
+		// 점프 호스트에는 최종 호스트의 패스워드를 보내지 않음
+		auth, err := n.authMethods(jumpKeyPath(n.config, j), false)
+		if err != nil {
+			closeJumps()
+			return nil, nil, fmt.Errorf("점프 호스트 #%d: %v", i+1, err)
+		}
+		client, err := n.connect(ctx, jumpClients, addr, j.GetUser(n.config.SSHUser), auth)
+		if err != nil {
+			closeJumps()
+			return nil, nil, fmt.Errorf("점프 호스트 #%d: %w", i+1, err)
+		}
+		jumpClients = append(jumpClients, client)
+	}
+
+	addr := net.JoinHostPort(n.config.SSHHost, strconv.Itoa(n.config.SSHPort))
+	auth, err := n.authMethods(n.config.SSHKeyPath, true)
+	if err != nil {
+		closeJumps()
+		return nil, nil, err
+	}
+	client, err := n.connect(ctx, jumpClients, addr, n.config.SSHUser, auth)
+	if err != nil {
+		closeJumps()
+		return nil, nil, err
+	}
+	return client, jumpClients, nil
+}
+
+// connect 주소에 연결 후 핸드셰이크 및 인증 수행 (점프 호스트가 있으면 마지막 점프 호스트를 통해 연결)
+func (n *nativeTransport) connect(ctx context.Context, jumps []*ssh.Client, addr, user string, auth []ssh.AuthMethod) (*ssh.Client, error) {
+	var conn net.Conn
+	var err error
+	if len(jumps) == 0 {
+		dialer := net.Dialer{Timeout: nativeConnectTimeout}
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = jumps[len(jumps)-1].Dial("tcp", addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("SSH 서버 %s 연결 실패: %v", addr, err)
+	}
+
+	clientConfig := &ssh.ClientConfig{
+		User:            user,
+		Auth:            auth,
+		HostKeyCallback: n.hostKeyCallback,
+		Timeout:         nativeConnectTimeout,
+	}
+
+	// 핸드셰이크가 멈추지 않도록 타임아웃 또는 ctx 취소 시 연결을 닫음
+	// (점프 호스트를 통한 연결은 데드라인을 지원하지 않음)
+	timer := time.AfterFunc(nativeConnectTimeout, func() { conn.Close() })
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	c, chans, reqs, err := ssh.NewClientConn(conn, addr, clientConfig)
+	timer.Stop()
+	stop()
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("SSH 핸드셰이크 실패 (%s): %w", addr, err)
+	}
+
+	return ssh.NewClient(c, chans, reqs), nil
+}
+
+// authMethods 인증 방식 목록 구성 (키, 에이전트, 패스워드 순)
+func (n *nativeTransport) authMethods(keyPath string, withPassword bool) ([]ssh.AuthMethod, error) {
+	var methods []ssh.AuthMethod
+
+	if keyPath != "" {
+		signer, err := n.loadSigner(keyPath)
+		if err != nil {
+			return nil, err
+		}
+		methods = append(methods, ssh.PublicKeys(signer))
+	}
+
+	if n.agentConn != nil {
+		methods = append(methods, ssh.PublicKeysCallback(agent.NewClient(n.agentConn).Signers))
+	}
+
+	if withPassword && n.config.SSHPassword != "" {
+		password := n.config.SSHPassword
+		methods = append(methods,
+			ssh.Password(password),
+			ssh.KeyboardInteractive(func(user, instruction string, questions []string, echos []bool) ([]string, error) {
+				answers := make([]string, len(questions))
+				for i := range answers {
+					answers[i] = password
+				}
+				return answers, nil
+			}),
+		)
+	}
+
+	if len(methods) == 0 {
+		return nil, fmt.Errorf("사용 가능한 SSH 인증 방식이 없습니다")
+	}
+	return methods, nil
+}
+
+// openAgent SSH 에이전트 연결 (SSH_AUTH_SOCK)
+func (n *nativeTransport) openAgent() error {
+	socket := os.Getenv("SSH_AUTH_SOCK")
+	if socket == "" {
+		return fmt.Errorf("SSH 에이전트를 찾을 수 없습니다 (SSH_AUTH_SOCK 미설정)")
+	}
+	conn, err := net.Dial("unix", socket)
+	if err != nil {
+		return fmt.Errorf("SSH 에이전트 연결 실패: %v", err)
+	}
+	n.agentConn = conn
+	return nil
+}
+
+// loadSigner 개인키 파일 로드 (암호화된 키는 ssh_password를 패스프레이즈로 사용)
+func (n *nativeTransport) loadSigner(keyPath string) (ssh.Signer, error) {
+	keyData, err := os.ReadFile(keyPath)
+	if err != nil {
+		return nil, fmt.Errorf("SSH 키 파일 읽기 실패: %v", err)
+	}
+
+	signer, err := ssh.ParsePrivateKey(keyData)
+	if _, ok := err.(*ssh.PassphraseMissingError); ok && n.config.SSHPassword != "" {
+		signer, err = ssh.ParsePrivateKeyWithPassphrase(keyData, []byte(n.config.SSHPassword))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("SSH 키 파일 파싱 실패: %v", err)
+	}
+	return signer, nil
+}
+
+// closeAgent 에이전트 연결 닫기
+func (n *nativeTransport) closeAgent() {
+	if n.agentConn != nil {
+		n.agentConn.Close()
+		n.agentConn = nil
+	}
+}
+
+// acceptLoop 리스너로 들어온 연결을 받아 대상으로 포워딩
+func (n *nativeTransport) acceptLoop(fw *nativeForward) {
+	defer n.wg.Done()
+
+	for {
+		conn, err := fw.listener.Accept()
+		if err != nil {
+			return
+		}
+		n.wg.Add(1)
+		go n.forward(fw, conn)
+	}
+}
+
+// forward 들어온 연결 하나를 포워딩 대상으로 연결
+func (n *nativeTransport) forward(fw *nativeForward, conn net.Conn) {
+	defer n.wg.Done()
+	defer conn.Close()
+
+	addr := fw.target
+	dynamic := fw.config.GetType() == config.TypeDynamic
+	if dynamic {
+		var err error
+		if addr, err = socksAccept(conn); err != nil {
+			if err != io.EOF {
+				log.Printf("터널 '%s' SOCKS5 협상 실패: %v", n.config.Name, err)
+			}
+			return
+		}
+	}
+
+	target, err := fw.dialTarget("tcp", addr)
+	if err != nil {
+		log.Printf("터널 '%s' 대상 %s 연결 실패: %v", n.config.Name, addr, err)
+		if dynamic {
+			socksReply(conn, socksReplyHostUnreachable)
+		}
+		return
+	}
+	defer target.Close()
+
+	if dynamic {
+		if err := socksReply(conn, socksReplySucceeded); err != nil {
+			return
+		}
+	}
+
+	pipe(conn, target)
+}
+
+// keepAlive 주기적으로 keepalive 요청을 보내고 응답이 없으면 연결 종료
+// 서버가 응답하지 않으면 SendRequest가 반환되지 않으므로 ServerAliveInterval × ServerAliveCountMax만큼만 기다림
+func (n *nativeTransport) keepAlive() {
+	ticker := time.NewTicker(nativeKeepAliveInterval)
+	defer ticker.Stop()
+
+	fails := 0
+	for {
+		select {
+		case <-n.done:
+			return
+		case <-ticker.C:
+			result := make(chan error, 1) // 시간 초과 후에 응답이 와도 goroutine이 막히지 않도록 버퍼 사용
+			go func() {
+				_, _, err := n.client.SendRequest("keepalive@openssh.com", true, nil)
+				result <- err
+			}()
+
+			select {
+			case <-n.done:
+				return
+			case <-time.After(nativeKeepAliveInterval * nativeKeepAliveMaxFails):
+				log.Printf("터널 '%s' keepalive 응답 시간 초과 - 연결 종료", n.config.Name)
+				n.close()
+				return
+			case err := <-result:
+				if err != nil {
+					fails++
+					if fails >= nativeKeepAliveMaxFails {
+						log.Printf("터널 '%s' keepalive 응답 없음 - 연결 종료", n.config.Name)
+						n.close()
+						return
+					}
+				} else {
+					fails = 0
+				}
+			}
+		}
+	}
+}
+
+// pipe 두 연결 사이에 데이터를 양방향으로 복사 (한쪽이 끝나면 둘 다 닫음)
+func pipe(a, b net.Conn) {
+	done := make(chan struct{}, 2)
+	go func() {
+		io.Copy(a, b)
+		done <- struct{}{}
+	}()
+	go func() {
+		io.Copy(b, a)
+		done <- struct{}{}
+	}()
+
+	<-done
+	a.Close()
+	b.Close()
+	<-done
+}
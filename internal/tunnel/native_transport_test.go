@@ -0,0 +1,487 @@
+package tunnel
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"tunnels/internal/config"
+	"tunnels/internal/sshtest"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+// startEchoServer 받은 데이터를 그대로 돌려주는 TCP 서버 시작 후 포트 반환
+func startEchoServer(t *testing.T) int {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("에코 서버 리슨 실패: %v", err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				io.Copy(conn, conn)
+			}()
+		}
+	}()
+	return l.Addr().(*net.TCPAddr).Port
+}
+
+// newTestKey ed25519 키 생성
+func newTestKey(t *testing.T) (ed25519.PrivateKey, ssh.Signer) {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("키 생성 실패: %v", err)
+	}
+	signer, err := ssh.NewSignerFromKey(priv)
+	if err != nil {
+		t.Fatalf("키 변환 실패: %v", err)
+	}
+	return priv, signer
+}
+
+// writeTestKey 개인키를 OpenSSH 형식 파일로 저장하고 경로 반환
+func writeTestKey(t *testing.T, priv ed25519.PrivateKey) string {
+	t.Helper()
+	block, err := ssh.MarshalPrivateKey(priv, "")
+	if err != nil {
+		t.Fatalf("키 직렬화 실패: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "id_ed25519")
+	if err := os.WriteFile(path, pem.EncodeToMemory(block), 0600); err != nil {
+		t.Fatalf("키 파일 저장 실패: %v", err)
+	}
+	return path
+}
+
+// testTunnelConfig 테스트 서버에 패스워드로 연결하는 터널 설정 (known_hosts는 임시 디렉토리)
+func testTunnelConfig(t *testing.T, s *sshtest.Server) config.TunnelConfig {
+	return config.TunnelConfig{
+		Name:           "test",
+		SSHHost:        "127.0.0.1",
+		SSHPort:        s.Port,
+		SSHUser:        sshtest.User,
+		SSHPassword:    sshtest.Password,
+		Transport:      config.TransportNative,
+		KnownHostsFile: filepath.Join(t.TempDir(), "known_hosts"),
+	}
+}
+
+// startTransport native 전송 시작 (테스트가 끝나면 중지)
+func startTransport(t *testing.T, cfg config.TunnelConfig) *nativeTransport {
+	t.Helper()
+	n := newNativeTransport(cfg)
+	if err := n.Start(context.Background()); err != nil {
+		t.Fatalf("Start 실패: %v", err)
+	}
+	t.Cleanup(func() { n.Stop() })
+	return n
+}
+
+// assertEcho addr로 연결해서 보낸 데이터가 그대로 돌아오는지 확인
+func assertEcho(t *testing.T, conn net.Conn) {
+	t.Helper()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	msg := []byte("hello tunnel")
+	if _, err := conn.Write(msg); err != nil {
+		t.Fatalf("쓰기 실패: %v", err)
+	}
+	buf := make([]byte, len(msg))
+	if _, err := io.ReadFull(conn, buf); err != nil {
+		t.Fatalf("읽기 실패: %v", err)
+	}
+	if !bytes.Equal(buf, msg) {
+		t.Fatalf("에코 결과가 다름: %q", buf)
+	}
+}
+
+// dialLocal 127.0.0.1:port로 연결
+func dialLocal(t *testing.T, port int) net.Conn {
+	t.Helper()
+	conn, err := net.DialTimeout("tcp", "127.0.0.1:"+strconv.Itoa(port), 5*time.Second)
+	if err != nil {
+		t.Fatalf("포트 %d 연결 실패: %v", port, err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestNativeTransportLocalForward(t *testing.T) {
+	s := sshtest.NewServer(t)
+	echoPort := startEchoServer(t)
+
+	cfg := testTunnelConfig(t, s)
+	cfg.LocalPort = sshtest.FreePort(t)
+	cfg.RemoteHost = "127.0.0.1"
+	cfg.RemotePort = echoPort
+	n := startTransport(t, cfg)
+
+	if !n.Alive() {
+		t.Fatal("시작 후 Alive가 false")
+	}
+	assertEcho(t, dialLocal(t, cfg.LocalPort))
+}
+
+func TestNativeTransportRemoteForward(t *testing.T) {
+	s := sshtest.NewServer(t)
+	echoPort := startEchoServer(t)
+
+	cfg := testTunnelConfig(t, s)
+	cfg.Type = config.TypeRemote
+	cfg.RemoteBindAddress = "127.0.0.1"
+	cfg.RemotePort = sshtest.FreePort(t)
+	cfg.LocalPort = echoPort
+	startTransport(t, cfg)
+
+	// SSH 서버 쪽 포트로 들어온 연결이 로컬 에코 서버로 전달됨
+	assertEcho(t, dialLocal(t, cfg.RemotePort))
+}
+
+func TestNativeTransportDynamicForward(t *testing.T) {
+	s := sshtest.NewServer(t)
+	echoPort := startEchoServer(t)
+
+	cfg := testTunnelConfig(t, s)
+	cfg.Type = config.TypeDynamic
+	cfg.LocalPort = sshtest.FreePort(t)
+	startTransport(t, cfg)
+
+	if err := checkSocksProxy(cfg.LocalPort); err != nil {
+		t.Fatalf("SOCKS5 인사 실패: %v", err)
+	}
+
+	conn := dialLocal(t, cfg.LocalPort)
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	// 인사 후 127.0.0.1:echoPort로 CONNECT
+	if _, err := conn.Write([]byte{socksVersion5, 1, socksMethodNoAuth}); err != nil {
+		t.Fatalf("SOCKS5 인사 전송 실패: %v", err)
+	}
+	reply := make([]byte, 2)
+	if _, err := io.ReadFull(conn, reply); err != nil || reply[1] != socksMethodNoAuth {
+		t.Fatalf("SOCKS5 인사 응답 오류: %x %v", reply, err)
+	}
+	request := []byte{socksVersion5, socksCmdConnect, 0, socksAtypIPv4, 127, 0, 0, 1, 0, 0}
+	binary.BigEndian.PutUint16(request[8:], uint16(echoPort))
+	if _, err := conn.Write(request); err != nil {
+		t.Fatalf("SOCKS5 요청 전송 실패: %v", err)
+	}
+	reply = make([]byte, 10)
+	if _, err := io.ReadFull(conn, reply); err != nil || reply[1] != socksReplySucceeded {
+		t.Fatalf("SOCKS5 CONNECT 응답 오류: %x %v", reply, err)
+	}
+
+	assertEcho(t, conn)
+}
+
+func TestNativeTransportAuth(t *testing.T) {
+	echoPort := startEchoServer(t)
+
+	tests := []struct {
+		name    string
+		setup   func(t *testing.T, s *sshtest.Server, cfg *config.TunnelConfig)
+		wantErr bool
+	}{
+		{
+			name: "패스워드",
+			setup: func(t *testing.T, s *sshtest.Server, cfg *config.TunnelConfig) {
+			},
+		},
+		{
+			name: "잘못된 패스워드",
+			setup: func(t *testing.T, s *sshtest.Server, cfg *config.TunnelConfig) {
+				cfg.SSHPassword = "wrong"
+			},
+			wantErr: true,
+		},
+		{
+			name: "키 파일",
+			setup: func(t *testing.T, s *sshtest.Server, cfg *config.TunnelConfig) {
+				priv, signer := newTestKey(t)
+				s.Authorize(signer.PublicKey())
+				cfg.SSHPassword = ""
+				cfg.SSHKeyPath = writeTestKey(t, priv)
+			},
+		},
+		{
+			name: "허용되지 않은 키 파일",
+			setup: func(t *testing.T, s *sshtest.Server, cfg *config.TunnelConfig) {
+				priv, _ := newTestKey(t)
+				cfg.SSHPassword = ""
+				cfg.SSHKeyPath = writeTestKey(t, priv)
+			},
+			wantErr: true,
+		},
+		{
+			name: "에이전트",
+			setup: func(t *testing.T, s *sshtest.Server, cfg *config.TunnelConfig) {
+				if runtime.GOOS == "windows" {
+					t.Skip("SSH_AUTH_SOCK 유닉스 소켓 에이전트는 유닉스 전용")
+				}
+				priv, signer := newTestKey(t)
+				s.Authorize(signer.PublicKey())
+				t.Setenv("SSH_AUTH_SOCK", startTestAgent(t, priv))
+				cfg.SSHPassword = ""
+				cfg.UseAgent = true
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := sshtest.NewServer(t)
+			cfg := testTunnelConfig(t, s)
+			cfg.LocalPort = sshtest.FreePort(t)
+			cfg.RemoteHost = "127.0.0.1"
+			cfg.RemotePort = echoPort
+			tt.setup(t, s, &cfg)
+
+			n := newNativeTransport(cfg)
+			err := n.Start(context.Background())
+			if tt.wantErr {
+				if err == nil {
+					n.Stop()
+					t.Fatal("인증 실패가 오류 없이 끝남")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Start 실패: %v", err)
+			}
+			defer n.Stop()
+			assertEcho(t, dialLocal(t, cfg.LocalPort))
+		})
+	}
+}
+
+// startTestAgent 키 하나를 가진 SSH 에이전트를 유닉스 소켓으로 시작하고 소켓 경로 반환
+func startTestAgent(t *testing.T, priv ed25519.PrivateKey) string {
+	t.Helper()
+	keyring := agent.NewKeyring()
+	if err := keyring.Add(agent.AddedKey{PrivateKey: priv}); err != nil {
+		t.Fatalf("에이전트 키 추가 실패: %v", err)
+	}
+
+	// 유닉스 소켓 경로 길이 제한 때문에 짧은 임시 디렉토리 사용
+	dir, err := os.MkdirTemp("", "agent")
+	if err != nil {
+		t.Fatalf("임시 디렉토리 생성 실패: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	socket := filepath.Join(dir, "sock")
+	l, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatalf("에이전트 리슨 실패: %v", err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				agent.ServeAgent(keyring, conn)
+			}()
+		}
+	}()
+	return socket
+}
+
+func TestNativeTransportHostKey(t *testing.T) {
+	s := sshtest.NewServer(t)
+	_, other := newTestKey(t)
+	hostLine := func(key ssh.PublicKey) string {
+		return "[127.0.0.1]:" + strconv.Itoa(s.Port) + " " + strings.TrimSpace(string(ssh.MarshalAuthorizedKey(key))) + "\n"
+	}
+
+	start := func(cfg config.TunnelConfig) error {
+		cfg.LocalPort = sshtest.FreePort(t)
+		cfg.RemoteHost = "127.0.0.1"
+		cfg.RemotePort = 1
+		n := newNativeTransport(cfg)
+		err := n.Start(context.Background())
+		n.Stop()
+		return err
+	}
+
+	t.Run("accept-new는 처음 보는 키를 기록", func(t *testing.T) {
+		cfg := testTunnelConfig(t, s)
+		if err := start(cfg); err != nil {
+			t.Fatalf("Start 실패: %v", err)
+		}
+		data, err := os.ReadFile(cfg.KnownHostsFile)
+		if err != nil {
+			t.Fatalf("known_hosts 읽기 실패: %v", err)
+		}
+		if string(data) != hostLine(s.HostKey.PublicKey()) {
+			t.Fatalf("known_hosts 내용이 다름: %q", data)
+		}
+
+		// 기록된 키는 strict에서도 허용
+		cfg.HostKeyPolicy = config.HostKeyStrict
+		if err := start(cfg); err != nil {
+			t.Fatalf("기록된 키로 strict 연결 실패: %v", err)
+		}
+	})
+
+	t.Run("strict는 등록되지 않은 호스트를 거부", func(t *testing.T) {
+		cfg := testTunnelConfig(t, s)
+		cfg.HostKeyPolicy = config.HostKeyStrict
+		err := start(cfg)
+		if err == nil || !strings.Contains(err.Error(), "known_hosts에 등록되지 않은 호스트") {
+			t.Fatalf("등록되지 않은 호스트 거부 오류가 아님: %v", err)
+		}
+		if data, _ := os.ReadFile(cfg.KnownHostsFile); len(data) != 0 {
+			t.Fatalf("strict인데 known_hosts에 기록됨: %q", data)
+		}
+	})
+
+	t.Run("known_hosts와 다른 키는 거부", func(t *testing.T) {
+		cfg := testTunnelConfig(t, s)
+		if err := os.WriteFile(cfg.KnownHostsFile, []byte(hostLine(other.PublicKey())), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if err := start(cfg); !errors.Is(err, ErrHostKeyMismatch) {
+			t.Fatalf("ErrHostKeyMismatch가 아님: %v", err)
+		}
+	})
+
+	t.Run("고정 지문이 다르면 거부", func(t *testing.T) {
+		cfg := testTunnelConfig(t, s)
+		cfg.HostKeyFingerprint = ssh.FingerprintSHA256(other.PublicKey())
+		if err := start(cfg); !errors.Is(err, ErrHostKeyMismatch) {
+			t.Fatalf("ErrHostKeyMismatch가 아님: %v", err)
+		}
+		if _, err := os.Stat(cfg.KnownHostsFile); !os.IsNotExist(err) {
+			t.Fatalf("고정 지문 사용 시 known_hosts를 건드림: %v", err)
+		}
+	})
+
+	t.Run("고정 지문이 같으면 known_hosts 없이 허용", func(t *testing.T) {
+		cfg := testTunnelConfig(t, s)
+		cfg.HostKeyPolicy = config.HostKeyStrict
+		cfg.HostKeyFingerprint = ssh.FingerprintSHA256(s.HostKey.PublicKey())
+		if err := start(cfg); err != nil {
+			t.Fatalf("Start 실패: %v", err)
+		}
+	})
+}
+
+func TestNativeTransportStop(t *testing.T) {
+	s := sshtest.NewServer(t)
+	echoPort := startEchoServer(t)
+
+	cfg := testTunnelConfig(t, s)
+	cfg.LocalPort = sshtest.FreePort(t)
+	cfg.RemoteHost = "127.0.0.1"
+	cfg.RemotePort = echoPort
+	n := newNativeTransport(cfg)
+	if err := n.Start(context.Background()); err != nil {
+		t.Fatalf("Start 실패: %v", err)
+	}
+
+	// 포워딩 중인 연결이 있어도 Stop이 끝나고 연결이 닫혀야 함
+	conn := dialLocal(t, cfg.LocalPort)
+	assertEcho(t, conn)
+
+	stopped := make(chan struct{})
+	go func() {
+		n.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stop이 끝나지 않음")
+	}
+
+	select {
+	case <-n.Done():
+	default:
+		t.Fatal("Stop 후 Done이 닫히지 않음")
+	}
+	if n.Alive() {
+		t.Fatal("Stop 후 Alive가 true")
+	}
+
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	if _, err := conn.Read(make([]byte, 1)); err == nil {
+		t.Fatal("Stop 후 포워딩 연결이 닫히지 않음")
+	}
+	if c, err := net.DialTimeout("tcp", "127.0.0.1:"+strconv.Itoa(cfg.LocalPort), time.Second); err == nil {
+		c.Close()
+		t.Fatal("Stop 후 로컬 포트가 열려 있음")
+	}
+}
+
+func TestNativeTransportServerDisconnect(t *testing.T) {
+	s := sshtest.NewServer(t)
+
+	cfg := testTunnelConfig(t, s)
+	cfg.LocalPort = sshtest.FreePort(t)
+	cfg.RemoteHost = "127.0.0.1"
+	cfg.RemotePort = 1
+	n := startTransport(t, cfg)
+
+	// 서버가 연결을 끊으면 Done이 닫히고 리스너도 닫혀야 상태 확인에서 감지됨
+	s.CloseConns()
+	select {
+	case <-n.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("연결이 끊겼는데 Done이 닫히지 않음")
+	}
+	if n.Alive() {
+		t.Fatal("연결이 끊겼는데 Alive가 true")
+	}
+}
+
+func TestNativeTransportCancelContext(t *testing.T) {
+	s := sshtest.NewServer(t)
+
+	cfg := testTunnelConfig(t, s)
+	cfg.LocalPort = sshtest.FreePort(t)
+	cfg.RemoteHost = "127.0.0.1"
+	cfg.RemotePort = 1
+
+	ctx, cancel := context.WithCancel(context.Background())
+	n := newNativeTransport(cfg)
+	if err := n.Start(ctx); err != nil {
+		t.Fatalf("Start 실패: %v", err)
+	}
+	defer n.Stop()
+
+	cancel()
+	select {
+	case <-n.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("ctx가 취소됐는데 Done이 닫히지 않음")
+	}
+}
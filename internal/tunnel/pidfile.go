@@ -0,0 +1,72 @@
+package tunnel
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// pidFileMu 터널마다 같은 PID 기록 파일을 고치므로 읽고 다시 쓰는 동안 보호
+var pidFileMu sync.Mutex
+
+// recordPID 실행한 ssh 프로세스 PID를 기록 파일에 추가 (비정상 종료 후 다음 실행에서 이 인스턴스의 프로세스만 정리하기 위해)
+func recordPID(path string, pid int) {
+	updatePIDFile(path, func(pids []int) []int {
+		return append(pids, pid)
+	})
+}
+
+// forgetPID 종료된 ssh 프로세스 PID를 기록 파일에서 제거
+func forgetPID(path string, pid int) {
+	updatePIDFile(path, func(pids []int) []int {
+		kept := pids[:0]
+		for _, p := range pids {
+			if p != pid {
+				kept = append(kept, p)
+			}
+		}
+		return kept
+	})
+}
+
+// updatePIDFile PID 목록을 읽어 update 결과로 다시 저장 (목록이 비면 파일 삭제)
+func updatePIDFile(path string, update func([]int) []int) {
+	if path == "" {
+		return
+	}
+
+	pidFileMu.Lock()
+	defer pidFileMu.Unlock()
+
+	pids := update(ReadPIDFile(path))
+	if len(pids) == 0 {
+		os.Remove(path)
+		return
+	}
+
+	lines := make([]string, len(pids))
+	for i, pid := range pids {
+		lines[i] = strconv.Itoa(pid)
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0600); err != nil {
+		log.Printf("ssh 프로세스 PID 기록 실패: %v", err)
+	}
+}
+
+// ReadPIDFile PID 기록 파일의 PID 목록 (파일이 없거나 읽을 수 없으면 nil, 잘못된 줄은 무시)
+func ReadPIDFile(path string) []int {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	var pids []int
+	for _, line := range strings.Fields(string(data)) {
+		if pid, err := strconv.Atoi(line); err == nil && pid > 0 {
+			pids = append(pids, pid)
+		}
+	}
+	return pids
+}
@@ -0,0 +1,300 @@
+package tunnel
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"tunnels/internal/config"
+)
+
+// errProbeFailed 프로브 실패 (SSH 세션은 살아 있지만 원격 대상이 정상 응답하지 않음)
+var errProbeFailed = errors.New("프로브 실패")
+
+// probeHostEnv, probePortEnv command 프로브에 전달하는 환경 변수
+const (
+	probeHostEnv = "TUNNELS_PROBE_HOST"
+	probePortEnv = "TUNNELS_PROBE_PORT"
+)
+
+// runProbe 포워딩에 설정된 프로브 실행
+func runProbe(p *config.ProbeConfig, port int) error {
+	var err error
+	switch p.Type {
+	case config.ProbeTCP:
+		err = probeTCP(p, port)
+	case config.ProbeHTTP:
+		err = probeHTTP(p, port)
+	case config.ProbePostgres:
+		err = probePostgres(p, port)
+	case config.ProbeMySQL:
+		err = probeMySQL(p, port)
+	case config.ProbeRedis:
+		err = probeRedis(p, port)
+	case config.ProbeCommand:
+		err = probeCommand(p, port)
+	default:
+		err = fmt.Errorf("지원하지 않는 프로브 종류: %s", p.Type)
+	}
+	if err != nil {
+		return fmt.Errorf("%s %w (포트 %d): %v", p.Type, errProbeFailed, port, err)
+	}
+	return nil
+}
+
+// dialProbe 로컬 포트에 연결하고 전체 타임아웃을 deadline으로 설정
+func dialProbe(p *config.ProbeConfig, port int) (net.Conn, error) {
+	timeout := p.GetTimeout()
+	conn, err := net.DialTimeout("tcp", fmt.Sprintf("127.0.0.1:%d", port), timeout)
+	if err != nil {
+		return nil, fmt.Errorf("로컬 포트 연결 실패: %v", err)
+	}
+	conn.SetDeadline(time.Now().Add(timeout))
+	return conn, nil
+}
+
+// probeTCP 터널을 통해 연결 후 응답 확인
+//
+// expect가 있으면 응답에 해당 문자열이 나올 때까지 읽고, 없으면 원격 대상이 연결을 끊지 않는지 확인
+// (ssh는 로컬 연결을 먼저 받은 뒤 원격 연결에 실패하면 바로 끊으므로 EOF는 원격 연결 실패를 뜻함)
+func probeTCP(p *config.ProbeConfig, port int) error {
+	conn, err := dialProbe(p, port)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if p.Send != "" {
+		if _, err := io.WriteString(conn, p.Send); err != nil {
+			return fmt.Errorf("데이터 전송 실패: %v", err)
+		}
+	}
+
+	var received []byte
+	buf := make([]byte, 1024)
+	for {
+		n, err := conn.Read(buf)
+		received = append(received, buf[:n]...)
+		if p.Expect == "" && n > 0 {
+			return nil // 배너를 보내는 서버
+		}
+		if p.Expect != "" && strings.Contains(string(received), p.Expect) {
+			return nil
+		}
+		if err == nil {
+			continue
+		}
+
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() && p.Expect == "" {
+			return nil // 먼저 말하지 않는 서버: 연결이 유지되면 정상
+		}
+		if len(received) > 0 || (errors.As(err, &netErr) && netErr.Timeout()) {
+			return fmt.Errorf("응답에서 %q를 찾지 못함 (받은 데이터: %q)", p.Expect, truncate(string(received), 64))
+		}
+		if err == io.EOF {
+			return fmt.Errorf("원격 대상이 연결을 종료함")
+		}
+		return fmt.Errorf("응답 읽기 실패: %v", err)
+	}
+}
+
+// probeHTTP HTTP GET 요청 후 응답 코드 확인 (리다이렉트는 따라가지 않음)
+func probeHTTP(p *config.ProbeConfig, port int) error {
+	scheme := "http"
+	if p.TLS {
+		scheme = "https"
+	}
+	url := fmt.Sprintf("%s://127.0.0.1:%d%s", scheme, port, p.GetPath())
+
+	client := &http.Client{
+		Timeout: p.GetTimeout(),
+		Transport: &http.Transport{
+			Proxy:             nil,
+			DisableKeepAlives: true,
+			// 인증서는 원격 호스트 이름으로 발급되어 127.0.0.1과 맞지 않으므로 확인하지 않음
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	resp, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if p.Status != 0 {
+		if resp.StatusCode != p.Status {
+			return fmt.Errorf("응답 코드 %d (기대값 %d)", resp.StatusCode, p.Status)
+		}
+		return nil
+	}
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("응답 코드 %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// postgresSSLRequestCode PostgreSQL SSLRequest 메시지 코드
+const postgresSSLRequestCode = 80877103
+
+// probePostgres SSLRequest를 보내 PostgreSQL 서버가 S/N으로 응답하는지 확인 (인증 불필요)
+func probePostgres(p *config.ProbeConfig, port int) error {
+	conn, err := dialProbe(p, port)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	request := make([]byte, 8)
+	binary.BigEndian.PutUint32(request[0:4], 8)
+	binary.BigEndian.PutUint32(request[4:8], postgresSSLRequestCode)
+	if _, err := conn.Write(request); err != nil {
+		return fmt.Errorf("SSLRequest 전송 실패: %v", err)
+	}
+
+	reply := make([]byte, 1)
+	if _, err := io.ReadFull(conn, reply); err != nil {
+		return fmt.Errorf("응답 없음: %v", err)
+	}
+	if reply[0] != 'S' && reply[0] != 'N' {
+		return fmt.Errorf("PostgreSQL 서버가 아닌 응답: %q", reply[0])
+	}
+	return nil
+}
+
+// probeMySQL MySQL 서버가 연결 직후 보내는 초기 핸드셰이크 패킷 확인 (인증 불필요)
+func probeMySQL(p *config.ProbeConfig, port int) error {
+	conn, err := dialProbe(p, port)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// 패킷 헤더: 길이 3바이트 (little endian) + 시퀀스 번호 1바이트
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return fmt.Errorf("핸드셰이크 패킷 없음: %v", err)
+	}
+	length := int(header[0]) | int(header[1])<<8 | int(header[2])<<16
+	if length == 0 || length > 64*1024 {
+		return fmt.Errorf("MySQL 서버가 아닌 응답 (패킷 길이 %d)", length)
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(conn, payload); err != nil {
+		return fmt.Errorf("핸드셰이크 패킷 읽기 실패: %v", err)
+	}
+
+	switch payload[0] {
+	case 0x0a: // 프로토콜 버전 10
+		return nil
+	case 0xff: // 오류 패킷: 오류 코드 2바이트 + 메시지 (접속 허용 안 됨, 연결 수 초과 등)
+		if len(payload) < 3 {
+			return fmt.Errorf("MySQL 오류 응답")
+		}
+		code := binary.LittleEndian.Uint16(payload[1:3])
+		return fmt.Errorf("MySQL 오류 %d: %s", code, truncate(string(payload[3:]), 128))
+	default:
+		return fmt.Errorf("MySQL 서버가 아닌 응답 (프로토콜 버전 %d)", payload[0])
+	}
+}
+
+// probeRedis PING을 보내 PONG 응답 확인 (인증이 필요한 서버의 NOAUTH 응답도 정상으로 봄)
+func probeRedis(p *config.ProbeConfig, port int) error {
+	conn, err := dialProbe(p, port)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := io.WriteString(conn, "PING\r\n"); err != nil {
+		return fmt.Errorf("PING 전송 실패: %v", err)
+	}
+
+	line, err := bufio.NewReader(io.LimitReader(conn, 1024)).ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("응답 없음: %v", err)
+	}
+	line = strings.TrimSpace(line)
+	switch {
+	case line == "+PONG", strings.HasPrefix(line, "-NOAUTH"):
+		return nil
+	case strings.HasPrefix(line, "-"):
+		return fmt.Errorf("Redis 오류: %s", truncate(line[1:], 128))
+	default:
+		return fmt.Errorf("Redis 서버가 아닌 응답: %q", truncate(line, 64))
+	}
+}
+
+// probeCommand 사용자 명령 실행 후 종료 코드 확인 ({port}와 TUNNELS_PROBE_PORT로 로컬 포트 전달)
+func probeCommand(p *config.ProbeConfig, port int) error {
+	command := strings.ReplaceAll(p.Command, config.ProbePortPlaceholder, strconv.Itoa(port))
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", command)
+	} else {
+		cmd = exec.Command("sh", "-c", command)
+	}
+	configureProcess(cmd)
+	cmd.Env = append(os.Environ(),
+		probeHostEnv+"=127.0.0.1",
+		probePortEnv+"="+strconv.Itoa(port),
+	)
+	output := newOutputBuffer()
+	cmd.Stdout = output
+	cmd.Stderr = output
+	// 종료된 셸의 하위 프로세스가 출력 파이프를 잡고 있어도 오래 기다리지 않음
+	cmd.WaitDelay = time.Second
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("명령 실행 실패: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-time.After(p.GetTimeout()):
+		// 셸이 실행한 하위 프로세스까지 종료 (Unix는 프로세스 그룹 단위)
+		killProcess(cmd.Process)
+		<-done
+		return fmt.Errorf("시간 초과 (%s)", p.GetTimeout())
+	}
+
+	if err != nil {
+		if line := output.LastLine(); line != "" {
+			return fmt.Errorf("%v (%s)", err, line)
+		}
+		return err
+	}
+	return nil
+}
+
+// truncate 오류 메시지에 넣을 문자열 길이 제한
+func truncate(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+	return s[:limit] + "..."
+}
@@ -0,0 +1,145 @@
+package tunnel
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+)
+
+// SOCKS5 프로토콜 상수 (RFC 1928)
+const (
+	socksVersion5 = 0x05
+
+	socksMethodNoAuth       = 0x00
+	socksMethodNoAcceptable = 0xff
+
+	socksCmdConnect = 0x01
+
+	socksAtypIPv4   = 0x01
+	socksAtypDomain = 0x03
+	socksAtypIPv6   = 0x04
+
+	socksReplySucceeded           = 0x00
+	socksReplyGeneralFailure      = 0x01
+	socksReplyHostUnreachable     = 0x04
+	socksReplyCommandNotSupported = 0x07
+	socksReplyAddrNotSupported    = 0x08
+)
+
+// socksHandshakeTimeout SOCKS5 협상 타임아웃
+const socksHandshakeTimeout = 10 * time.Second
+
+// socksAccept 클라이언트와 SOCKS5 협상 후 CONNECT 대상 주소 반환 (인증 없음만 지원)
+func socksAccept(conn net.Conn) (string, error) {
+	conn.SetDeadline(time.Now().Add(socksHandshakeTimeout))
+	defer conn.SetDeadline(time.Time{})
+
+	// 인사: VER NMETHODS METHODS...
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return "", fmt.Errorf("SOCKS5 인사 읽기 실패: %v", err)
+	}
+	if header[0] != socksVersion5 {
+		return "", fmt.Errorf("지원하지 않는 SOCKS 버전: %d", header[0])
+	}
+	methods := make([]byte, header[1])
+	if _, err := io.ReadFull(conn, methods); err != nil {
+		return "", fmt.Errorf("SOCKS5 인증 방식 읽기 실패: %v", err)
+	}
+
+	noAuth := false
+	for _, m := range methods {
+		if m == socksMethodNoAuth {
+			noAuth = true
+			break
+		}
+	}
+	if !noAuth {
+		conn.Write([]byte{socksVersion5, socksMethodNoAcceptable})
+		return "", fmt.Errorf("SOCKS5 클라이언트가 인증 없음 방식을 지원하지 않음")
+	}
+	if _, err := conn.Write([]byte{socksVersion5, socksMethodNoAuth}); err != nil {
+		return "", err
+	}
+
+	// 요청: VER CMD RSV ATYP DST.ADDR DST.PORT
+	request := make([]byte, 4)
+	if _, err := io.ReadFull(conn, request); err != nil {
+		if err == io.EOF {
+			// 상태 확인처럼 인사만 하고 연결을 끊은 경우
+			return "", io.EOF
+		}
+		return "", fmt.Errorf("SOCKS5 요청 읽기 실패: %v", err)
+	}
+	if request[1] != socksCmdConnect {
+		socksReply(conn, socksReplyCommandNotSupported)
+		return "", fmt.Errorf("지원하지 않는 SOCKS5 명령: %d", request[1])
+	}
+
+	var host string
+	switch request[3] {
+	case socksAtypIPv4, socksAtypIPv6:
+		size := net.IPv4len
+		if request[3] == socksAtypIPv6 {
+			size = net.IPv6len
+		}
+		ip := make([]byte, size)
+		if _, err := io.ReadFull(conn, ip); err != nil {
+			return "", err
+		}
+		host = net.IP(ip).String()
+	case socksAtypDomain:
+		length := make([]byte, 1)
+		if _, err := io.ReadFull(conn, length); err != nil {
+			return "", err
+		}
+		domain := make([]byte, length[0])
+		if _, err := io.ReadFull(conn, domain); err != nil {
+			return "", err
+		}
+		host = string(domain)
+	default:
+		socksReply(conn, socksReplyAddrNotSupported)
+		return "", fmt.Errorf("지원하지 않는 SOCKS5 주소 형식: %d", request[3])
+	}
+
+	port := make([]byte, 2)
+	if _, err := io.ReadFull(conn, port); err != nil {
+		return "", err
+	}
+
+	return net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(port)))), nil
+}
+
+// socksReply CONNECT 요청에 대한 응답 전송 (바인드 주소는 0.0.0.0:0)
+func socksReply(conn net.Conn, code byte) error {
+	_, err := conn.Write([]byte{socksVersion5, code, 0x00, socksAtypIPv4, 0, 0, 0, 0, 0, 0})
+	return err
+}
+
+// checkSocksProxy 로컬 SOCKS5 프록시에 인사를 보내 정상 응답하는지 확인
+func checkSocksProxy(port int) error {
+	addr := fmt.Sprintf("127.0.0.1:%d", port)
+	conn, err := net.DialTimeout("tcp", addr, healthCheckTimeout)
+	if err != nil {
+		return fmt.Errorf("SOCKS5 포트 %d 연결 실패: %v", port, err)
+	}
+	defer conn.Close()
+
+	conn.SetDeadline(time.Now().Add(healthCheckTimeout))
+	if _, err := conn.Write([]byte{socksVersion5, 1, socksMethodNoAuth}); err != nil {
+		return fmt.Errorf("SOCKS5 인사 전송 실패: %v", err)
+	}
+
+	reply := make([]byte, 2)
+	if _, err := io.ReadFull(conn, reply); err != nil {
+		return fmt.Errorf("SOCKS5 포트 %d 응답 없음: %v", port, err)
+	}
+	if reply[0] != socksVersion5 || reply[1] != socksMethodNoAuth {
+		return fmt.Errorf("SOCKS5 포트 %d 비정상 응답: %x", port, reply)
+	}
+	return nil
+}
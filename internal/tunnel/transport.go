@@ -0,0 +1,45 @@
+package tunnel
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"tunnels/internal/config"
+)
+
+// Transport 실제 SSH 연결과 포트 포워딩을 담당하는 전송 계층
+type Transport interface {
+	// Start 연결을 수립하고 포워딩 시작 (ctx 취소 시 연결 종료)
+	Start(ctx context.Context) error
+	// Stop 연결 및 포워딩 종료
+	Stop() error
+	// Alive SSH 세션이 살아 있는지 여부
+	Alive() bool
+	// Done 세션이 끝나면(프로세스 종료, 연결 끊김, Stop) 닫히는 채널 (Start 성공 후에만 사용)
+	Done() <-chan struct{}
+	// ExitCode ssh 프로세스 종료 코드 (native 전송이거나 아직 실행 중이면 -1)
+	ExitCode() int
+	// ForwardError 전송 계층이 알고 있는 포워딩별 실패 원인 (index는 GetForwards 순서, 없으면 nil)
+	ForwardError(index int) error
+}
+
+// newTransport 터널 설정에 맞는 전송 계층 생성 (output: ssh 프로세스 stderr를 받을 곳, exec 전용)
+func newTransport(cfg config.TunnelConfig, output io.Writer) (Transport, error) {
+	switch cfg.GetTransport() {
+	case config.TransportExec:
+		return newExecTransport(cfg, output), nil
+	case config.TransportNative:
+		return newNativeTransport(cfg), nil
+	default:
+		return nil, fmt.Errorf("지원하지 않는 전송 방식: %s", cfg.Transport)
+	}
+}
+
+// remoteBindAddress 원격 포워딩에서 SSH 서버가 리슨할 주소 (미지정 시 OpenSSH 기본값과 같은 localhost)
+func remoteBindAddress(f config.ForwardConfig) string {
+	if f.RemoteBindAddress == "" {
+		return "localhost"
+	}
+	return f.RemoteBindAddress
+}
@@ -2,15 +2,11 @@ package tunnel
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
-	"net"
-	"os"
-	"os/exec"
-	"runtime"
-	"strconv"
+	"strings"
 	"sync"
-	"syscall"
 	"time"
 
 	"tunnels/internal/config"
@@ -23,6 +19,7 @@ const (
 	StatusDisconnected Status = "disconnected"
 	StatusConnecting   Status = "connecting"
 	StatusConnected    Status = "connected"
+	StatusDegraded     Status = "degraded" // SSH 세션은 정상이지만 원격 대상이 프로브에 응답하지 않음
 	StatusError        Status = "error"
 )
 
@@ -30,15 +27,23 @@ const (
 type Tunnel struct {
 	config       config.TunnelConfig
 	status       Status
-	process      *exec.Cmd
+	transport    Transport
 	ctx          context.Context
 	cancel       context.CancelFunc
 	mu           sync.RWMutex
 	lastError    string
 	lastCheck    time.Time
-	retryCount   int    // 연속 실패 횟수
-	maxRetries   int    // 최대 재시도 횟수
-	lastSuccess  time.Time // 마지막 성공 시간
+	retryCount   int             // 연속 실패 횟수
+	gaveUp       bool            // 자동 재시작 중단 여부 (최대 재시도 초과, 호스트 키 불일치, 설정 오류)
+	nextRetry    time.Time       // 다음 자동 재시작 예정 시간 (예약이 없으면 zero)
+	lastSuccess  time.Time       // 마지막 성공 시간
+	forwards     []ForwardStatus // 포워딩별 마지막 확인 결과
+	output       *outputBuffer   // ssh 프로세스 stderr 최근 출력
+	failure      FailureReason   // 마지막 실패 원인 분류
+	exitCode     int             // 마지막 ssh 프로세스 종료 코드 (native 또는 종료 전이면 -1)
+	exitTime     time.Time       // 마지막 세션 종료 시간
+	restartTimer *time.Timer     // 예약된 자동 재시작
+	generation   uint64          // Stop 호출마다 증가 (중지 후 재시작 방지)
 }
 
 // NewTunnel 새 터널 인스턴스 생성
@@ -50,128 +55,306 @@ func NewTunnel(config config.TunnelConfig) *Tunnel {
 		ctx:        ctx,
 		cancel:     cancel,
 		retryCount: 0,
-		maxRetries: 3, // 최대 3번까지 재시도
+		output:     newOutputBuffer(),
+		exitCode:   -1,
 	}
 }
 
 // Start 터널 시작
 func (t *Tunnel) Start() error {
 	t.mu.Lock()
-	defer t.mu.Unlock()
+	return t.start()
+}
 
-	if t.status == StatusConnected || t.status == StatusConnecting {
+// start 터널 시작 (뮤텍스를 잡은 상태에서 호출, 반환 전에 해제)
+func (t *Tunnel) start() error {
+	if t.status == StatusConnected || t.status == StatusConnecting || t.status == StatusDegraded {
+		t.mu.Unlock()
 		return nil
 	}
 
+	// 오류 상태에서 직접 시작하는 경우 (모두 연결, 트레이 연결 등) 자동 재시작 상태를 초기화
+	// (중단 상태가 남아 있으면 다음 연결 끊김 때 재시작하지 않고, 예약 시간도 계속 표시됨)
+	var stale Transport
+	if t.status == StatusError {
+		t.cancelRestart()
+		t.retryCount = 0
+		t.gaveUp = false
+		t.cancel()
+		stale = t.transport
+		t.transport = nil
+	}
+
 	t.status = StatusConnecting
 	t.lastError = ""
+	t.failure = FailureNone
+	t.output.Reset()
 
-	// SSH 명령어 구성
-	cmd, err := t.buildSSHCommand()
+	// 설정에 맞는 전송 계층 구성
+	transport, err := newTransport(t.config, t.output)
 	if err != nil {
 		t.status = StatusError
 		t.lastError = err.Error()
+		t.failure = FailureUnknown
+		t.gaveUp = true // 설정 문제는 재시도해도 해결되지 않음
+		t.mu.Unlock()
+		if stale != nil {
+			stale.Stop()
+		}
 		return err
 	}
+	t.transport = transport
+	// Stop으로 취소된 context는 다시 쓸 수 없으므로 새로 생성
+	if t.ctx.Err() != nil {
+		t.ctx, t.cancel = context.WithCancel(context.Background())
+	}
+	ctx := t.ctx
+	t.mu.Unlock()
+
+	// 상태 확인 실패로 오류가 된 경우 이전 전송 계층이 아직 살아 있을 수 있음
+	if stale != nil {
+		stale.Stop()
+	}
 
-	// 프로세스 시작
-	t.process = exec.CommandContext(t.ctx, cmd[0], cmd[1:]...)
+	// 연결 수립은 시간이 걸릴 수 있으므로 뮤텍스 밖에서 수행
+	startErr := transport.Start(ctx)
 
-	// SSH 프로세스의 출력을 /dev/null로 리다이렉트 (Windows에서는 NUL)
-	t.process.Stdout = nil
-	t.process.Stderr = nil
+	t.mu.Lock()
+	defer t.mu.Unlock()
 
-	// Windows에서 콘솔 창 숨기기
-	if runtime.GOOS == "windows" {
-		t.process.SysProcAttr = &syscall.SysProcAttr{
-			HideWindow:    true,
-			CreationFlags: 0x08000000, // CREATE_NO_WINDOW
-		}
+	// 시작 중에 Stop/Restart가 호출된 경우
+	if t.transport != transport {
+		transport.Stop()
+		return nil
 	}
 
-	if err := t.process.Start(); err != nil {
+	if startErr != nil {
 		t.status = StatusError
-		t.lastError = fmt.Sprintf("프로세스 시작 실패: %v", err)
-		return err
+		t.failure = classifyFailure(append([]string{startErr.Error()}, t.output.Lines()...)...)
+		// 호스트 키 불일치는 재시도해도 해결되지 않으므로 자동 재시작 중단
+		if errors.Is(startErr, ErrHostKeyMismatch) {
+			t.gaveUp = true
+			t.lastError = startErr.Error()
+			log.Printf("터널 '%s' 호스트 키 불일치 - 자동 재시작 중단: %v", t.config.Name, startErr)
+			return startErr
+		}
+		// 네트워크가 아직 없는 경우 등을 위해 시작 실패도 재시도 정책에 따라 재시도
+		t.retryLater(startErr)
+		return startErr
 	}
 
-		// SSH 프로세스 모니터링은 제거 (연결 상태만 체크)
-
-	// 연결 상태 모니터링은 Manager에서 처리
+	// 프로세스 종료/연결 끊김은 즉시 감지하고, 포워딩 상태는 Manager의 주기적 확인으로 판단
+	go t.watch(transport)
 
 	// 초기 상태는 연결 중으로 설정 (실제 연결 확인 후 변경됨)
 	t.status = StatusConnecting
-	log.Printf("터널 '%s' SSH 프로세스 시작됨: %s", t.config.Name, t.GetConnectionString())
+	log.Printf("터널 '%s' 시작됨 (%s): %s", t.config.Name, t.config.GetTransport(), t.connectionString())
 	return nil
 }
 
+// watch 전송 계층이 끝나기를 기다렸다가 즉시 상태 반영 및 재연결 예약
+func (t *Tunnel) watch(transport Transport) {
+	<-transport.Done()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// Stop/Restart로 교체된 경우 (의도한 종료)
+	if t.transport != transport {
+		return
+	}
+
+	t.exitCode = transport.ExitCode()
+	t.exitTime = time.Now()
+
+	var err error
+	switch {
+	case t.exitCode >= 0:
+		err = fmt.Errorf("SSH 프로세스 종료됨 (종료 코드 %d)", t.exitCode)
+	case t.config.GetTransport() == config.TransportExec:
+		err = fmt.Errorf("SSH 프로세스 종료됨 (시그널)")
+	default:
+		err = fmt.Errorf("SSH 연결 종료됨")
+	}
+	t.fail(err)
+}
+
+// fail 연결 실패 처리: 원인 분류, 오류 상태 전환 및 재시작 예약 (뮤텍스를 잡은 상태에서 호출)
+// 이미 오류 상태면 같은 실패를 두 번 세지 않도록 무시
+func (t *Tunnel) fail(err error) {
+	if t.status != StatusConnected && t.status != StatusConnecting && t.status != StatusDegraded {
+		return
+	}
+
+	// 포트 확인 실패나 프로세스 종료만으로는 원인을 알 수 없으므로 ssh 출력으로 분류
+	t.failure = classifyFailure(t.output.Lines()...)
+	if t.failure == FailureNone {
+		t.failure = FailureUnknown
+	}
+	if line := t.output.LastLine(); line != "" {
+		err = fmt.Errorf("%v (%s)", err, line)
+	}
+
+	t.status = StatusError
+
+	// 호스트 키가 바뀐 경우는 재시도해도 해결되지 않음
+	if t.failure == FailureHostKey {
+		t.gaveUp = true
+		t.lastError = err.Error()
+		log.Printf("터널 '%s' 호스트 키 문제 - 자동 재시작 중단: %v", t.config.Name, err)
+		return
+	}
+
+	t.retryLater(err)
+}
+
+// degrade 프로브만 실패한 경우 처리 (뮤텍스를 잡은 상태에서 호출)
+// SSH 세션은 살아 있으므로 재시작하지 않고 재시도 횟수에도 넣지 않으며, 다음 확인에서 프로브가 성공하면 연결 상태로 돌아감
+func (t *Tunnel) degrade(err error) {
+	if t.status != StatusConnected && t.status != StatusConnecting && t.status != StatusDegraded {
+		return
+	}
+
+	if t.status != StatusDegraded {
+		log.Printf("터널 '%s' 프로브 실패 - SSH 세션은 유지하고 계속 확인: %v", t.config.Name, err)
+	}
+	t.cancelRestart()
+	t.status = StatusDegraded
+	t.lastError = err.Error()
+	t.failure = FailureProbe
+	t.retryCount = 0
+}
+
+// retryLater 실패 횟수를 세고 재시도 정책에 따라 자동 재시작 예약 (뮤텍스를 잡은 상태에서 호출)
+func (t *Tunnel) retryLater(err error) {
+	policy := t.config.GetRetryPolicy()
+	t.retryCount++
+
+	if !policy.Unlimited() && t.retryCount > policy.MaxAttempts {
+		t.gaveUp = true
+		t.nextRetry = time.Time{}
+		t.lastError = fmt.Sprintf("최대 재시도 횟수(%d) 초과: %v", policy.MaxAttempts, err)
+		log.Printf("터널 '%s' 최대 재시도 횟수(%d) 초과 - 자동 재시작 중단", t.config.Name, policy.MaxAttempts)
+		return
+	}
+
+	delay := policy.Delay(t.retryCount)
+	if policy.Unlimited() {
+		t.lastError = fmt.Sprintf("%v (재시도 %d회)", err, t.retryCount)
+	} else {
+		t.lastError = fmt.Sprintf("%v (재시도 %d/%d)", err, t.retryCount, policy.MaxAttempts)
+	}
+	log.Printf("터널 '%s' 연결 실패: %v - %s 후 자동 재시작 (%d번째)", t.config.Name, err, delay.Round(time.Second), t.retryCount)
+	t.scheduleRestart(delay)
+}
+
+// scheduleRestart 잠시 후 자동 재시작 예약 (뮤텍스를 잡은 상태에서 호출)
+// 그 사이 Stop/Restart가 호출되었거나 이미 복구된 경우 재시작하지 않음
+func (t *Tunnel) scheduleRestart(delay time.Duration) {
+	t.cancelRestart()
+
+	transport := t.transport
+	t.nextRetry = time.Now().Add(delay)
+	t.restartTimer = time.AfterFunc(delay, func() {
+		t.mu.Lock()
+		if t.transport != transport || t.status != StatusError {
+			t.mu.Unlock()
+			return
+		}
+		// 시작 실패는 retryLater에서 기록하고 다음 재시도를 예약함
+		t.restart()
+	})
+}
+
+// cancelRestart 예약된 자동 재시작 취소 (뮤텍스를 잡은 상태에서 호출)
+func (t *Tunnel) cancelRestart() {
+	if t.restartTimer != nil {
+		t.restartTimer.Stop()
+		t.restartTimer = nil
+	}
+	t.nextRetry = time.Time{}
+}
+
 // Stop 터널 중지
 func (t *Tunnel) Stop() error {
 	t.mu.Lock()
-	defer t.mu.Unlock()
+
+	// 예약된 재시작이나 진행 중인 재시작이 중지된 터널을 다시 켜지 않도록 세대 증가
+	t.generation++
+	t.cancelRestart()
 
 	if t.status == StatusDisconnected {
+		t.mu.Unlock()
 		return nil
 	}
 
 	t.cancel()
 
-	if t.process != nil && t.process.Process != nil {
-		// Windows에서는 직접 Kill 사용 (SIGTERM 미지원)
-		if runtime.GOOS == "windows" {
-			if err := t.process.Process.Kill(); err != nil {
-				log.Printf("터널 '%s' 프로세스 강제 종료 실패: %v", t.config.Name, err)
-			}
-		} else {
-			// Linux/macOS에서는 SIGTERM으로 정상 종료 시도
-			if err := t.process.Process.Signal(os.Interrupt); err != nil {
-				log.Printf("터널 '%s' 프로세스 SIGTERM 실패: %v", t.config.Name, err)
-			}
-
-			// 잠시 대기 후 강제 종료
-			time.Sleep(2 * time.Second)
-
-			// ProcessState가 nil이 아닌지 확인 후 Exited() 호출
-			if t.process.ProcessState != nil && !t.process.ProcessState.Exited() {
-				if err := t.process.Process.Kill(); err != nil {
-					log.Printf("터널 '%s' 프로세스 강제 종료 실패: %v", t.config.Name, err)
-				}
-			}
-		}
-
-		// 프로세스 완전 종료 대기
-		time.Sleep(1 * time.Second)
-	}
+	// 종료 감시 goroutine이 의도한 종료로 알 수 있도록 먼저 분리
+	transport := t.transport
+	t.transport = nil
 
 	t.status = StatusDisconnected
 	t.lastError = ""
-	log.Printf("터널 '%s' 중지됨", t.config.Name)
+	t.failure = FailureNone
+	t.forwards = nil
+	t.retryCount = 0
+	t.gaveUp = false
+	name := t.config.Name
+	t.mu.Unlock()
+
+	// 전송 계층 종료는 대기가 길 수 있으므로 (exec 최대 3초, native는 포워딩 정리) 뮤텍스 밖에서 수행
+	if transport != nil {
+		if err := transport.Stop(); err != nil {
+			log.Printf("터널 '%s' 연결 종료 실패: %v", name, err)
+		}
+	}
+	log.Printf("터널 '%s' 중지됨", name)
 	return nil
 }
 
 // Restart 터널 재시작
 func (t *Tunnel) Restart() error {
 	t.mu.Lock()
+	return t.restart()
+}
 
-	// 재시도 횟수가 최대값에 도달한 경우 재시작하지 않음
-	if t.retryCount >= t.maxRetries {
+// restart 터널 재시작 (뮤텍스를 잡은 상태에서 호출, 반환 전에 해제)
+func (t *Tunnel) restart() error {
+	// 자동 재시작이 중단된 경우 재시작하지 않음 (설정을 다시 로드하면 초기화됨)
+	if t.gaveUp {
 		t.mu.Unlock()
-		log.Printf("터널 '%s' 최대 재시도 횟수(%d) 초과로 재시작 중단", t.config.Name, t.maxRetries)
-		return fmt.Errorf("최대 재시도 횟수(%d) 초과", t.maxRetries)
+		log.Printf("터널 '%s' 자동 재시작 중단 상태로 재시작하지 않음", t.config.Name)
+		return fmt.Errorf("자동 재시작 중단됨: %s", t.lastError)
 	}
 
-	// 기존 프로세스 중지
-	if t.process != nil && t.process.Process != nil {
-		t.process.Process.Kill()
-	}
+	t.cancelRestart()
+
+	// 기존 연결 중지 (context 취소로 ssh 프로세스도 종료됨)
+	t.cancel()
+	transport := t.transport
+	t.transport = nil
+	t.status = StatusDisconnected
+	generation := t.generation
 
 	// 새 context 생성 (기존 context가 취소되었을 수 있음)
 	t.ctx, t.cancel = context.WithCancel(context.Background())
 
 	t.mu.Unlock()
 
+	if transport != nil {
+		transport.Stop()
+	}
+
 	time.Sleep(1 * time.Second) // 잠시 대기
-	return t.Start()
+
+	// 대기 중에 Stop이 호출된 경우 다시 시작하지 않음
+	t.mu.Lock()
+	if t.generation != generation {
+		t.mu.Unlock()
+		return nil
+	}
+	return t.start()
 }
 
 // GetStatus 터널 상태 반환
@@ -183,6 +366,8 @@ func (t *Tunnel) GetStatus() Status {
 
 // GetConfig 설정 반환
 func (t *Tunnel) GetConfig() config.TunnelConfig {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
 	return t.config
 }
 
@@ -193,111 +378,87 @@ func (t *Tunnel) GetLastError() string {
 	return t.lastError
 }
 
-// GetLastCheck 마지막 체크 시간 반환
-func (t *Tunnel) GetLastCheck() time.Time {
+// GetFailureReason 마지막 실패 원인 분류 반환 (오류 상태가 아니면 FailureNone)
+func (t *Tunnel) GetFailureReason() FailureReason {
 	t.mu.RLock()
 	defer t.mu.RUnlock()
-	return t.lastCheck
+	return t.failure
 }
 
-// buildSSHCommand SSH 명령어 구성
-func (t *Tunnel) buildSSHCommand() ([]string, error) {
-	if runtime.GOOS != "windows" {
-		return nil, fmt.Errorf("이 프로그램은 Windows에서만 동작합니다")
-	}
-
-	// Windows OpenSSH 클라이언트 사용
-	cmd := []string{"ssh"}
-
-	// 포트 설정
-	if t.config.SSHPort != 22 {
-		cmd = append(cmd, "-p", strconv.Itoa(t.config.SSHPort))
-	}
-
-	// 로컬 포트 포워딩 설정
-	localForward := fmt.Sprintf("%d:%s:%d", t.config.LocalPort, t.config.RemoteHost, t.config.RemotePort)
-	cmd = append(cmd, "-L", localForward)
-
-	// 키 파일 설정
-	if t.config.SSHKeyPath != "" {
-		cmd = append(cmd, "-i", t.config.SSHKeyPath)
-	}
-
-	// 패스워드 인증 사용 시
-	if t.config.SSHPassword != "" {
-		// Windows에서는 sshpass가 기본 제공되지 않으므로 키 기반 인증 권장
-		log.Printf("경고: 패스워드 인증은 Windows OpenSSH에서 제한적입니다. 키 기반 인증을 권장합니다.")
-	}
-
-	// 연결 타임아웃 설정
-	cmd = append(cmd, "-o", "ConnectTimeout=10")
-	cmd = append(cmd, "-o", "ServerAliveInterval=20")
-	cmd = append(cmd, "-o", "ServerAliveCountMax=3")
-
-	// 호스트 키 확인 비활성화 (개발용)
-	cmd = append(cmd, "-o", "StrictHostKeyChecking=no")
-	cmd = append(cmd, "-o", "UserKnownHostsFile=NUL")
-
-	// 백그라운드 실행을 위한 옵션
-	cmd = append(cmd, "-N")
-
-	// 사용자@호스트
-	cmd = append(cmd, fmt.Sprintf("%s@%s", t.config.SSHUser, t.config.SSHHost))
+// GetOutput ssh 프로세스 stderr 최근 출력 반환 (마지막 연결 시도 기준, 오래된 줄부터)
+func (t *Tunnel) GetOutput() []string {
+	return t.output.Lines()
+}
 
-	return cmd, nil
+// GetExitInfo 마지막 세션 종료 정보 반환 (종료 코드는 native 전송이거나 종료된 적이 없으면 -1)
+func (t *Tunnel) GetExitInfo() (int, time.Time) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.exitCode, t.exitTime
 }
 
+// GetNextRetry 다음 자동 재시작 예정 시간 반환 (예약이 없으면 zero)
+func (t *Tunnel) GetNextRetry() time.Time {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.nextRetry
+}
 
+// GetLastCheck 마지막 체크 시간 반환
+func (t *Tunnel) GetLastCheck() time.Time {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.lastCheck
+}
 
 // CheckConnection 연결 상태 확인
+// 프로브는 타임아웃까지 걸릴 수 있으므로 설정과 전송 계층만 뮤텍스 안에서 가져오고 확인은 뮤텍스 밖에서 수행
 func (t *Tunnel) CheckConnection() {
 	t.mu.Lock()
-	defer t.mu.Unlock()
-
 	t.lastCheck = time.Now()
 
 	// 이미 오류 상태이고 재시도 횟수가 최대값에 도달한 경우 연결 확인하지 않음
-	if t.status == StatusError && t.retryCount >= t.maxRetries {
+	if t.status == StatusError && t.gaveUp {
+		t.mu.Unlock()
 		return
 	}
+	forwards := t.config.GetForwards()
+	transport := t.transport
+	t.mu.Unlock()
+
+	// 터널 종류에 맞는 방식으로 상태 확인
+	statuses, err := checkHealth(forwards, transport)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
 
-	// 로컬 포트가 열려있는지 확인
-	conn, err := net.DialTimeout("tcp", fmt.Sprintf("127.0.0.1:%d", t.config.LocalPort), 5*time.Second)
+	// 확인 중에 Stop/Restart로 전송 계층이 바뀐 경우 결과를 버림
+	if t.transport != transport {
+		return
+	}
+	t.forwards = statuses
 	if err != nil {
-		if t.status == StatusConnected || t.status == StatusConnecting {
-			t.retryCount++
-			t.status = StatusError
-			t.lastError = fmt.Sprintf("로컬 포트 %d 연결 실패: %v (재시도 %d/%d)", t.config.LocalPort, err, t.retryCount, t.maxRetries)
-
-			if t.retryCount >= t.maxRetries {
-				log.Printf("터널 '%s' 최대 재시도 횟수(%d) 초과 - 자동 재시작 중단", t.config.Name, t.maxRetries)
-				t.lastError = fmt.Sprintf("최대 재시도 횟수(%d) 초과: %v", t.maxRetries, err)
-			} else {
-				log.Printf("터널 '%s' 로컬 포트 연결 실패: %v - 자동 재시작 시도 (%d/%d)", t.config.Name, err, t.retryCount, t.maxRetries)
-
-				// 자동 재시작 시도 (뮤텍스 해제 후)
-				go func() {
-					time.Sleep(2 * time.Second) // 잠시 대기 후 재시작
-					if err := t.Restart(); err != nil {
-						log.Printf("터널 '%s' 자동 재시작 실패: %v", t.config.Name, err)
-					}
-				}()
-			}
+		// 세션이 살아 있는데 프로브만 실패했으면 원격 대상 문제이므로 SSH를 재시작하지 않음
+		if errors.Is(err, errProbeFailed) && transport != nil && transport.Alive() {
+			t.degrade(err)
+			return
 		}
+		t.fail(err)
 		return
 	}
-	conn.Close()
 
 	// 연결 성공 시 상태 업데이트 및 재시도 횟수 리셋
 	if t.status == StatusConnecting {
 		t.status = StatusConnected
 		t.lastError = ""
+		t.failure = FailureNone
 		t.retryCount = 0 // 재시도 횟수 리셋
 		t.lastSuccess = time.Now()
 		log.Printf("터널 '%s' 연결 성공", t.config.Name)
-	} else if t.status == StatusError {
+	} else if t.status == StatusError || t.status == StatusDegraded {
 		t.status = StatusConnected
 		t.lastError = ""
+		t.failure = FailureNone
 		t.retryCount = 0 // 재시도 횟수 리셋
 		t.lastSuccess = time.Now()
 		log.Printf("터널 '%s' 연결 복구됨", t.config.Name)
@@ -306,13 +467,44 @@ func (t *Tunnel) CheckConnection() {
 
 // GetConnectionString 연결 문자열 반환
 func (t *Tunnel) GetConnectionString() string {
-	return fmt.Sprintf("127.0.0.1:%d -> %s:%d (via %s@%s:%d)",
-		t.config.LocalPort,
-		t.config.RemoteHost,
-		t.config.RemotePort,
-		t.config.SSHUser,
-		t.config.SSHHost,
-		t.config.SSHPort)
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.connectionString()
+}
+
+// connectionString 연결 문자열 (뮤텍스를 잡은 상태에서 호출)
+func (t *Tunnel) connectionString() string {
+	forwards := t.config.GetForwards()
+	parts := make([]string, len(forwards))
+	for i, f := range forwards {
+		parts[i] = forwardString(f)
+	}
+
+	via := fmt.Sprintf("%s@%s:%d", t.config.SSHUser, t.config.SSHHost, t.config.SSHPort)
+	if t.config.SSHAlias != "" {
+		via = "ssh_config " + t.config.SSHAlias
+		if t.config.SSHUser != "" {
+			via = fmt.Sprintf("ssh_config %s@%s", t.config.SSHUser, t.config.SSHAlias)
+		}
+	}
+	if len(t.config.JumpHosts) > 0 {
+		via = fmt.Sprintf("%s, jump %s", via, jumpString(t.config))
+	}
+
+	return fmt.Sprintf("%s (via %s)", strings.Join(parts, ", "), via)
+}
+
+// forwardString 포워딩 하나의 연결 문자열
+func forwardString(f config.ForwardConfig) string {
+	switch f.GetType() {
+	case config.TypeDynamic:
+		return fmt.Sprintf("127.0.0.1:%d (SOCKS5)", f.LocalPort)
+	case config.TypeRemote:
+		return fmt.Sprintf("%s:%d (remote) -> %s:%d",
+			remoteBindAddress(f), f.RemotePort, f.GetLocalHost(), f.LocalPort)
+	default:
+		return fmt.Sprintf("127.0.0.1:%d -> %s:%d", f.LocalPort, f.RemoteHost, f.RemotePort)
+	}
 }
 
 // IsHealthy 터널이 정상 상태인지 확인
@@ -323,16 +515,15 @@ func (t *Tunnel) IsHealthy() bool {
 }
 
 // UpdateConfig 설정 업데이트
+// 연결에 영향을 주는 설정이 바뀐 경우에만 재시작 (재시도 정책만 바뀌면 다음 재시도부터 적용)
 func (t *Tunnel) UpdateConfig(newConfig config.TunnelConfig) {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
-	// 설정이 변경되었으면 재시작
-	if t.config != newConfig {
-		t.config = newConfig
-		if t.status == StatusConnected {
-			go t.Restart()
-		}
+	restart := !t.config.SameConnection(newConfig)
+	t.config = newConfig
+	if restart && (t.status == StatusConnected || t.status == StatusDegraded) {
+		go t.Restart()
 	}
 }
 
@@ -343,6 +534,7 @@ func (t *Tunnel) SetErrorStatus(errorMsg string) {
 
 	t.status = StatusError
 	t.lastError = errorMsg
-	t.retryCount = t.maxRetries // 재시도 횟수를 최대값으로 설정하여 재시도 방지
+	t.failure = classifyFailure(errorMsg)
+	t.gaveUp = true // 자동 재시작 방지
 	log.Printf("터널 '%s' 오류 상태 설정: %s", t.config.Name, errorMsg)
-}
\ No newline at end of file
+}
@@ -1,76 +1,38 @@
 package main
 
 import (
-	"embed"
-	"io"
 	"log"
 	"os"
-	"os/signal"
-	"path/filepath"
-	"runtime"
-	"syscall"
 
-	"tunnels/internal/app"
-
-	"github.com/getlantern/systray"
+	"tunnels/internal/tunnel"
 )
 
-//go:embed assets/icons/*.ico
-var iconAssets embed.FS
-
 func main() {
-	// Windows에서 콘솔 창 숨기기
-	if runtime.GOOS == "windows" {
-		hideConsoleWindow()
+	// ssh가 SSH_ASKPASS로 실행한 경우 패스워드만 전달하고 종료 (트레이 앱을 띄우지 않음)
+	if tunnel.IsAskpassHelper() {
+		os.Exit(tunnel.RunAskpassHelper(os.Args[1:]))
+	}
+
+	// CLI 하위 명령 (tunnels encrypt, tunnels --headless 등)
+	if handled, code := runCommand(os.Args[1:]); handled {
+		os.Exit(code)
 	}
 
-	// 콘솔 출력 완전 차단 (가장 먼저)
-	log.SetOutput(io.Discard)
-	os.Stdout = nil
-	os.Stderr = nil
+	// 시스템 트레이 앱 실행
+	runTray(os.Args[1:])
+}
 
+// setupLogFile 로그 파일 로테이션 후 로그 출력을 파일로 설정 (실패하면 로그 출력은 그대로 유지)
+func setupLogFile(logPath string) (*os.File, error) {
 	// 로그 파일 크기 확인 및 로테이션 (조용히)
-	logPath := "tunnels.log"
 	rotateLogIfNeeded(logPath)
 
-	// 로그 파일로 출력 설정
 	logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
-	if err == nil {
-		defer logFile.Close()
-		// 로그를 파일로만 출력하도록 설정
-		log.SetOutput(logFile)
-	}
-
-	// 설정 파일 경로
-	configPath := "tunnels.conf"
-	if len(os.Args) > 1 {
-		configPath = os.Args[1]
-	}
-
-	// 절대 경로로 변환
-	absConfigPath, err := filepath.Abs(configPath)
 	if err != nil {
-		// 에러 발생 시에도 조용히 종료
-		return
+		return nil, err
 	}
-
-	// 앱 인스턴스 생성
-	app := app.NewTunnelApp(absConfigPath, iconAssets)
-
-	// 시그널 처리 (Ctrl+C 등)
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
-
-	go func() {
-		<-sigChan
-		log.Println("시그널 수신 - 애플리케이션 종료 중...")
-		// SSH 프로세스 정리
-		app.CleanupSSHProcesses()
-		os.Exit(0)
-	}()
-
-	// 시스템 트레이 시작
-	systray.Run(app.OnReady, app.OnExit)
+	log.SetOutput(logFile)
+	return logFile, nil
 }
 
 // rotateLogIfNeeded 로그 파일 크기가 1MB를 초과하면 로테이션
@@ -97,26 +59,3 @@ func rotateLogIfNeeded(logPath string) error {
 
 	return nil
 }
-
-// hideConsoleWindow Windows에서 콘솔 창 숨기기
-func hideConsoleWindow() {
-	if runtime.GOOS != "windows" {
-		return
-	}
-
-	kernel32 := syscall.NewLazyDLL("kernel32.dll")
-	user32 := syscall.NewLazyDLL("user32.dll")
-
-	procGetConsoleWindow := kernel32.NewProc("GetConsoleWindow")
-	procShowWindow := user32.NewProc("ShowWindow")
-	procFreeConsole := kernel32.NewProc("FreeConsole")
-
-	// 콘솔 창 숨기기
-	consoleWindow, _, _ := procGetConsoleWindow.Call()
-	if consoleWindow != 0 {
-		procShowWindow.Call(consoleWindow, 0) // SW_HIDE = 0
-	}
-
-	// 콘솔 해제 (더 강력한 방법)
-	procFreeConsole.Call()
-}
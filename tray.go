@@ -0,0 +1,69 @@
+//go:build !notray
+
+package main
+
+import (
+	"embed"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"tunnels/internal/app"
+	"tunnels/internal/config"
+	"tunnels/internal/service"
+
+	"github.com/getlantern/systray"
+)
+
+//go:embed assets/icons/*.ico
+var iconAssets embed.FS
+
+// runTray 시스템 트레이 앱 실행 (args[0]은 설정 파일 경로, 기본값 tunnels.conf)
+func runTray(args []string) {
+	// Windows에서 콘솔 창 숨기기 (다른 OS에서는 아무 작업도 하지 않음)
+	hideConsoleWindow()
+
+	// 콘솔 출력 완전 차단 (가장 먼저)
+	log.SetOutput(io.Discard)
+	os.Stdout = nil
+	os.Stderr = nil
+
+	// 로그를 파일로만 출력하도록 설정
+	if logFile, err := setupLogFile("tunnels.log"); err == nil {
+		defer logFile.Close()
+	}
+
+	// 설정 파일 경로
+	configPath := "tunnels.conf"
+	if len(args) > 0 {
+		configPath = args[0]
+	}
+
+	// 절대 경로로 변환
+	absConfigPath, err := filepath.Abs(configPath)
+	if err != nil {
+		// 에러 발생 시에도 조용히 종료
+		return
+	}
+
+	// 앱 인스턴스 생성
+	app := app.NewTunnelApp(absConfigPath, iconAssets)
+
+	// 시그널 처리 (Ctrl+C 등)
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		<-sigChan
+		log.Println("시그널 수신 - 애플리케이션 종료 중...")
+		// SSH 프로세스 정리
+		service.CleanupSSHProcesses(config.PIDFilePath(absConfigPath))
+		os.Exit(0)
+	}()
+
+	// 시스템 트레이 시작
+	systray.Run(app.OnReady, app.OnExit)
+}
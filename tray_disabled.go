@@ -0,0 +1,15 @@
+//go:build notray
+
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// runTray 트레이 없이 빌드한 경우 (-tags notray) 헤드리스 모드 사용 안내 후 종료
+func runTray(args []string) {
+	fmt.Fprintln(os.Stderr, "이 빌드는 시스템 트레이를 포함하지 않습니다. tunnels --headless [설정파일]로 실행하세요.")
+	fmt.Fprint(os.Stderr, usage)
+	os.Exit(exitUsage)
+}